@@ -0,0 +1,147 @@
+package main
+
+import (
+	"math"
+	"sort"
+
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/data"
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/validator"
+)
+
+const (
+	MAXIMUM_ALTERNATIVES int = 5 // most alternative plans that can be requested
+	RUNNER_UPS           int = 3 // runner-ups listed for each step
+)
+
+// alternativePaths returns up to the provided number of sequences of recipes, each the cheapest doing without one of
+// the recipes of the cheapest sequence. Recipes are tried in order of how little more it costs to craft the cheapest
+// runner-up over the same skill levels, and each sequence returned must also do without one of the recipes used by
+// those returned before it, so that every alternative offers the player a way around a recipe they can't or won't
+// craft.
+func (pl *planner) alternativePaths(cheapest []segment, n int) [][]segment {
+	if n == 0 {
+		return nil
+	}
+
+	// how much more it costs to do without each recipe over the steps it's crafted for, as far as the runner-ups show
+	var ids []int
+	extra := make(map[int]int)
+	for _, seg := range cheapest {
+		if _, ok := extra[seg.recipe.ID]; !ok {
+			ids = append(ids, seg.recipe.ID)
+			extra[seg.recipe.ID] = math.MaxInt
+		}
+
+		learnCost, _, err := pl.learningCost(&seg.recipe)
+		if err != nil {
+			continue
+		}
+		if runnerUps := pl.runnerUps(seg, learnCost); len(runnerUps) > 0 {
+			extra[seg.recipe.ID] = validator.Min(extra[seg.recipe.ID], runnerUps[0].CostDifference)
+		}
+	}
+	sort.SliceStable(ids, func(i, j int) bool { return extra[ids[i]] < extra[ids[j]] })
+
+	var res [][]segment
+	found := [][]segment{cheapest}
+	for _, id := range ids {
+		if len(res) == n {
+			break
+		}
+
+		paths, err := pl.searchPaths(1, func(skill int) []candidate {
+			var res []candidate
+			for _, c := range pl.candidates(skill) {
+				if c.recipe.ID != id {
+					res = append(res, c)
+				}
+			}
+			return res
+		})
+		if err != nil {
+			pl.logger.Debugf("unable to plan without %v: %v", id, err)
+			continue
+		}
+
+		distinct := true
+		for _, path := range found {
+			if !avoids(paths[0], path) {
+				distinct = false
+				break
+			}
+		}
+		if distinct {
+			res = append(res, paths[0])
+			found = append(found, paths[0])
+		}
+	}
+
+	return res
+}
+
+// buildAlternatives builds a plan from each of the alternative sequences of recipes found when last planning, starting
+// afresh for each so that none of them relies upon what another crafted, and returns them cheapest first.
+func (pl *planner) buildAlternatives() ([]*data.Plan, error) {
+	var res []*data.Plan
+
+	for _, segments := range pl.alternatives {
+		pl.reset()
+
+		plan, err := pl.buildPlan(segments)
+		if err != nil {
+			return nil, err
+		}
+		pl.flagShortfalls(plan)
+
+		res = append(res, plan)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].TotalCost < res[j].TotalCost })
+
+	return res, nil
+}
+
+// runnerUps returns the cheapest recipes that could have been crafted instead of a segments recipe, over the same range
+// of skill levels, and how much more each would have cost. Recipes unable to cover the whole range aren't included.
+func (pl *planner) runnerUps(seg segment, learnCost int) []data.RunnerUp {
+	var res []data.RunnerUp
+
+	chosen := 0
+	pl.run(&candidate{recipe: seg.recipe, quote: pl.quote(&seg.recipe)}, seg.start, seg.end, math.MaxInt, func(level, crafts, score int) {
+		chosen = learnCost + score
+	})
+
+	for _, c := range pl.candidates(seg.start) {
+		if c.recipe.ID == seg.recipe.ID {
+			continue
+		}
+
+		// the chosen recipe's crafts would be free for another sharing its cooldown
+		maxCrafts := pl.remainingCrafts(&c.recipe)
+		if c.recipe.Cooldown > 0 && seg.recipe.Cooldown > 0 && c.recipe.CooldownName() == seg.recipe.CooldownName() {
+			maxCrafts += seg.crafts
+		}
+
+		cost, reached := 0, seg.start
+		pl.run(&c, seg.start, seg.end, maxCrafts, func(level, crafts, score int) {
+			cost, reached = c.learnCost+score, level
+		})
+		if reached < seg.end {
+			continue
+		}
+
+		res = append(res, data.RunnerUp{RecipeID: c.recipe.ID, RecipeName: c.recipe.Name, CostDifference: cost - chosen})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].CostDifference != res[j].CostDifference {
+			return res[i].CostDifference < res[j].CostDifference
+		}
+		return res[i].RecipeID < res[j].RecipeID
+	})
+	if len(res) > RUNNER_UPS {
+		res = res[:RUNNER_UPS]
+	}
+
+	return res
+}
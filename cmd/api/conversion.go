@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"math"
+
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/data"
+)
+
+// conversionCost returns the cost of a single unit of an item when converted from another, e.g. pigments milled from
+// herbs, along with the cheapest conversion to use. The whole cost of a conversion is charged to the item being priced,
+// so anything else it yields is a bonus. As with crafting, items already being made further up the chain can't be made
+// again.
+func (pl *planner) conversionCost(itemID int) (int, *data.Conversion, error) {
+	if pl.crafting[itemID] {
+		pl.cuts++
+		return math.MaxInt, nil, ErrCyclicCraft
+	}
+
+	pl.crafting[itemID] = true
+	defer delete(pl.crafting, itemID)
+
+	var (
+		cheapest   = math.MaxInt
+		conversion *data.Conversion
+	)
+
+	for _, c := range pl.stores.Conversions.GetByResult(itemID) {
+		if c.Method.Profession() == pl.player.Profession {
+			pl.dependsOn(c.Skill)
+		}
+		if !pl.player.CanConvert(c, pl.skill) {
+			continue
+		}
+
+		_, cost, err := pl.itemCost(c.ID)
+		if err != nil {
+			continue
+		}
+
+		if cost = int(math.Ceil(float64(cost*c.Quantity) / c.Yield(itemID))); cost < cheapest {
+			cheapest, conversion = cost, c
+		}
+	}
+
+	if conversion == nil {
+		return math.MaxInt, nil, errors.New("can't convert anything into this item")
+	}
+
+	return cheapest, conversion, nil
+}
+
+// acquireConverted adds a quantity of an item converted from another to the purchase order, acquiring enough of the
+// item converted by the cheapest route. Everything else the conversions are expected to yield is added to the
+// inventory for later use, as are any extras of the item.
+func (pl *planner) acquireConverted(id, qty int, order *data.PurchaseOrder) error {
+	cost, conversion, err := pl.conversionCost(id)
+	if err != nil {
+		return err
+	}
+
+	conversions := int(math.Ceil(float64(qty) / conversion.Yield(id)))
+	pl.crafting[id] = true
+	err = pl.acquire(conversion.ID, conversion.Quantity*conversions, order)
+	delete(pl.crafting, id)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range conversion.Yields {
+		produced := conversion.Produced(v.ID, conversions)
+		if v.ID != id {
+			pl.player.AddInventory(v.ID, produced)
+			continue
+		}
+
+		order.AddConverted(id, produced, cost*produced)
+		pl.player.AddInventory(id, produced-qty)
+	}
+
+	return nil
+}
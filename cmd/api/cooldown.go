@@ -0,0 +1,86 @@
+package main
+
+import (
+	"math"
+	"sort"
+
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/data"
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/validator"
+)
+
+const SECONDS_PER_DAY int = 86_400
+
+// cooldownDays returns the days spent waiting on a cooldown to make a number of crafts, the first of which can be made
+// straight away.
+func cooldownDays(crafts, cooldown int) float64 {
+	if crafts <= 1 {
+		return 0
+	}
+
+	return float64((crafts-1)*cooldown) / float64(SECONDS_PER_DAY)
+}
+
+// maximumCrafts returns the most times a recipe's cooldown allows it to be crafted within the number of days the player
+// allowed for the plan, which is unlimited if they didn't set one. The crafts are shared by every recipe subject to the
+// same cooldown, whether they're made to gain skill or to craft reagents.
+func (pl *planner) maximumCrafts(r *data.Recipe) int {
+	if r.Cooldown <= 0 || pl.input.MaxDays <= 0 {
+		return math.MaxInt
+	}
+
+	return pl.input.MaxDays*SECONDS_PER_DAY/r.Cooldown + 1
+}
+
+// remainingCrafts returns how many more times a recipe's cooldown allows it to be crafted, after the crafts recorded
+// so far.
+func (pl *planner) remainingCrafts(r *data.Recipe) int {
+	if r.Cooldown <= 0 {
+		return math.MaxInt
+	}
+
+	if v, ok := pl.cooldowns[r.CooldownName()]; ok {
+		return validator.Max(pl.maximumCrafts(r)-v.Crafts, 0)
+	}
+	return pl.maximumCrafts(r)
+}
+
+// cooldownOnPath returns the crafts subject to a cooldown made on the way to the link at an index of a skill level.
+func cooldownOnPath(best [][]planLink, start, skill, index int, name string) int {
+	crafts := 0
+	for skill > start {
+		link := best[skill-start][index]
+		if link.recipe.Cooldown > 0 && link.recipe.CooldownName() == name {
+			crafts += link.crafts
+		}
+		skill, index = link.from, link.prev
+	}
+
+	return crafts
+}
+
+// recordCooldown records a number of crafts of a recipe, so that the time spent waiting on its cooldown can be
+// reported.
+func (pl *planner) recordCooldown(r *data.Recipe, crafts int) {
+	if r.Cooldown <= 0 {
+		return
+	}
+
+	name := r.CooldownName()
+	if _, ok := pl.cooldowns[name]; !ok {
+		pl.cooldowns[name] = &data.Cooldown{Name: name}
+	}
+	pl.cooldowns[name].Crafts += crafts
+	pl.cooldowns[name].Days = cooldownDays(pl.cooldowns[name].Crafts, r.Cooldown)
+}
+
+// reportCooldowns records on the plan the crafts it makes subject to each cooldown. Separate cooldowns can be waited
+// on at the same time, so the calendar time the plan needs is that of the longest.
+func (pl *planner) reportCooldowns(plan *data.Plan) {
+	for _, v := range pl.cooldowns {
+		plan.Cooldowns = append(plan.Cooldowns, *v)
+		plan.CalendarDays = math.Max(plan.CalendarDays, v.Days)
+	}
+	sort.Slice(plan.Cooldowns, func(i, j int) bool {
+		return plan.Cooldowns[i].Name < plan.Cooldowns[j].Name
+	})
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"math"
+
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/data"
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/validator"
+)
+
+const COPPER_PER_GOLD int = 10_000
+
+// selfSupplied returns whether the player has offered to farm an item themselves, either by naming it or by naming
+// one of the ways it can be obtained (e.g. herbs are gathered and ore is mined).
+func (pl *planner) selfSupplied(id int) bool {
+	if validator.PermittedValue(id, pl.input.SelfSupplied) {
+		return true
+	}
+
+	item, err := pl.stores.Items.GetByID(id)
+	if err != nil {
+		return false
+	}
+
+	for _, source := range item.Source {
+		if validator.PermittedValue(source, pl.input.SelfSuppliedSources) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// farmingCost returns the value of the time the player spends farming a single unit of an item, based upon how many
+// they can farm an hour and what their time is worth.
+func (pl *planner) farmingCost(id int) (int, error) {
+	if !pl.selfSupplied(id) {
+		return math.MaxInt, errors.New("item isn't self supplied")
+	}
+
+	return int(math.Ceil(float64(pl.input.GoldPerHour*COPPER_PER_GOLD) / float64(pl.input.FarmRate))), nil
+}
+
+// reportFarming records on the plan how long the player is expected to spend farming the items they supply
+// themselves.
+func (pl *planner) reportFarming(plan *data.Plan) {
+	if pl.input.FarmRate <= 0 {
+		return
+	}
+
+	farmed := 0
+	for _, v := range plan.ShoppingList.Farmed {
+		farmed += v.Quantity
+	}
+
+	plan.FarmingHours = float64(farmed) / float64(pl.input.FarmRate)
+}
@@ -73,7 +73,7 @@ func (app *application) readJSON(w http.ResponseWriter, r *http.Request, dst any
 			return fmt.Errorf("body contains unknown key %s", fieldName)
 
 		// operator error - decode received non-nil pointer
-		case errors.As(err, *invalidUnmarshalError):
+		case errors.As(err, &invalidUnmarshalError):
 			panic(err)
 
 		// default error - everything else
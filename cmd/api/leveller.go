@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"math"
 	"net/http"
-	"strings"
 
 	"github.com/chrishollman/WotLK-Profession-Leveller/internal/data"
 	"github.com/chrishollman/WotLK-Profession-Leveller/internal/tsm"
@@ -13,14 +12,37 @@ import (
 )
 
 type plRequestPayload struct {
-	Region        string                 `json:"region"`
-	Server        string                 `json:"server"`
-	Faction       data.Faction           `json:"faction"`
-	StartLevel    int                    `json:"start_level"`
-	FinishLevel   int                    `json:"finish_level"`
-	Profession    data.Profession        `json:"profession"`
-	FilterSource  []data.Source          `json:"filter_source"`
-	FilterSkillup data.SkillupDifficulty `json:"filter_skillup"`
+	Region              string                 `json:"region"`
+	Server              string                 `json:"server"`
+	Faction             data.Faction           `json:"faction"`
+	Race                data.Race              `json:"race"`
+	StartLevel          int                    `json:"start_level"`
+	FinishLevel         int                    `json:"finish_level"`
+	Profession          data.Profession        `json:"profession"`
+	CurrentRank         data.Rank              `json:"current_rank"`
+	CharacterLevel      int                    `json:"character_level"`
+	SecondaryProfession data.Profession        `json:"secondary_profession"`
+	SecondaryLevel      int                    `json:"secondary_level"`
+	FilterSource        []data.Source          `json:"filter_source"`
+	FilterSkillup       data.SkillupDifficulty `json:"filter_skillup"`
+	Simulations         int                    `json:"simulations"`
+	Alternatives        int                    `json:"alternatives"`
+	MaxDays             int                    `json:"max_days"`
+	Resale              string                 `json:"resale"`
+	Objective           string                 `json:"objective"`
+	Pricing             *tsm.PricingStrategy   `json:"pricing"`
+
+	Inventory            []data.InventoryItem `json:"inventory"`
+	OwnedOpportunityCost bool                 `json:"owned_opportunity_cost"`
+	KnownRecipes         []int                `json:"known_recipes"`
+	KnownRecipesExport   string               `json:"known_recipes_export"`
+
+	SelfSupplied        []int         `json:"self_supplied"`
+	SelfSuppliedSources []data.Source `json:"self_supplied_sources"`
+	FarmRate            int           `json:"farm_rate"`     // items farmed per hour
+	GoldPerHour         int           `json:"gold_per_hour"` // what the players time is worth
+
+	Reputation map[data.Reputation]data.Standing `json:"reputation"` // standing with each faction selling recipes
 }
 
 // professionLevellingHandler is the handler for a profession levelling request. It handles various housekeeping aspects
@@ -44,17 +66,24 @@ func (app *application) professionLevellingHandler(w http.ResponseWriter, r *htt
 
 	res, err := app.levelup(input)
 	if err != nil {
+		// say where the players character level stops the plan, so they know what to level up to
+		var blocked *blockedError
+		if errors.As(err, &blocked) {
+			app.errorResponse(w, r, http.StatusBadRequest, envelope{"message": err.Error(), "blocked_at": blocked})
+			return
+		}
+
 		// TODO: better error handling
 		app.badRequestResponse(w, r, err)
+		return
 	}
 
 	app.writeJSON(w, http.StatusOK, envelope{"data": res}, nil)
 }
 
-// levelup is the main function
-func (app *application) levelup(input *plRequestPayload) (any, error) {
-
-	totalCost := 0
+// levelup is the main function. It determines the cheapest sequence of crafts taking the player from their starting
+// skill to their desired skill, and returns the resulting plan along with any alternatives requested.
+func (app *application) levelup(input *plRequestPayload) (*data.Plan, error) {
 
 	// Get the Auction House ID for the players server and faction
 	server, err := app.stores.Servers.GetByName(input.Server)
@@ -63,64 +92,48 @@ func (app *application) levelup(input *plRequestPayload) (any, error) {
 	}
 
 	// Preheat the cache with AH data if necessary
-	if err := app.tsmService.Preheat(server.AHIds[input.Faction]); err != nil {
+	if err := app.tsmService.Preheat(server.AuctionHouseID(input.Faction)); err != nil {
 		app.logger.Fatalf("unable to fetch TSM auction house data: %v", err)
 		// TODO: Meaningful error return
 		return nil, err
 	}
 
-	// maintain a player to remember known recipes and inventory items used for future crafts
-	player := data.NewPlayer(input.Profession, input.StartLevel, input.FinishLevel, server.AHIds[input.Faction])
-
-	// setup filters
-	fSource := data.NewFilterSource(input.FilterSource)
-	fSkillup := data.NewFilterSkillup(input.FilterSkillup)
-
-	for player.SkillCurrent < player.SkillDesired {
-		app.logger.Debugf("Assessing potential recipes for %v -> %v", player.SkillCurrent, player.SkillCurrent+1)
-
-		// get candidate recipes
-		var selectedCraft data.Recipe
-		var lowestCost int = math.MaxInt
-		candidates := app.stores.Recipes.GetFiltered(player.SkillCurrent, input.Profession, fSource, fSkillup)
-		for _, recipe := range candidates {
-			var cost int
-			numCrafts := app.getRequiredCrafts(player, &recipe)
-			price, list, err := app.recipeCost(player, &recipe)
-
-			switch {
-			case errors.Is(err, tsm.ErrIsBlacklisted):
-				// skip over this candidate because it has items we can't determine a cost for
-				continue
-			case err != nil:
-				app.logger.Errorf("unhandled error getting crafting cost of %v", recipe.ID)
-				continue
-			default:
-				// continue
-			}
-
-			cost = numCrafts * price
-			if cost < lowestCost {
-				lowestCost = cost
-				selectedCraft = recipe
-			}
-			app.logger.Debugf("	%v (%v) costs %v per craft and must (convervatively) be crafted %v times", recipe.ID, recipe.Name, intToGold(price), numCrafts)
-			app.logger.Debugf(" Requires purchases of following: %v\n", list)
+	pl := app.newPlanner(input, server)
+
+	plan, err := pl.plan()
+	if err != nil {
+		return nil, err
+	}
+
+	// if the auction house can't supply everything the plan needs, plan again with those items penalised
+	if shortfalls := pl.shortfalls(plan); len(shortfalls) > 0 {
+		pl.reset()
+		pl.illiquid = shortfalls
+
+		plan, err = pl.plan()
+		if err != nil {
+			return nil, err
 		}
+	}
+	pl.flagShortfalls(plan)
 
-		if lowestCost == math.MaxInt {
-			app.logger.Debugf("Unable to find a suitable crafts for %v -> %v, abandoning...", player.SkillCurrent, player.SkillCurrent+1)
-			return nil, nil
+	if input.Alternatives > 0 {
+		plan.Alternatives, err = pl.buildAlternatives()
+		if err != nil {
+			return nil, err
 		}
-		totalCost += lowestCost
-		app.logger.Debugf("Based upon the determined costs, %v is the cheapest craft costing %v for level %v", selectedCraft.Name, intToGold(lowestCost), player.SkillCurrent)
+	}
 
-		player.SkillCurrent++
+	if input.Simulations > 0 {
+		plan.Simulation, err = app.simulate(plan, input.Simulations)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	app.logger.Debugf("Total cost going from %v to %v was %v", input.StartLevel, player.SkillCurrent, intToGold(totalCost))
+	app.logger.Debugf("Total cost going from %v to %v was %v", input.StartLevel, input.FinishLevel, intToGold(plan.TotalCost))
 
-	return nil, nil
+	return plan, nil
 }
 
 // validateProfessionLevellingRequests runs various tests against the input received for the profession levelling
@@ -129,105 +142,241 @@ func (app *application) validateProfessionLevellingRequest(v *validator.Validato
 	v.Check(validator.PermittedValue(input.Server, app.getServers()), "server", "must be a valid server")
 	v.Check(validator.PermittedValue(input.Region, app.getRegions()), "region", "must be either 'EU' or 'US'")
 	v.Check(validator.PermittedValue(int(input.Faction), app.getFactions()), "faction", "must be 'Horde' or 'Alliance'")
-	v.Check(input.Profession.String() != data.UNDEFINED_TYPE, "profession", "must be a valid profession")
+	v.Check(input.Race == data.RACE_UNDEFINED || input.Race.String() != data.UNDEFINED_TYPE, "race", "must be a valid race")
+	v.Check(input.Profession.Plannable(), "profession", "must be a valid profession levelled by crafting")
+	if input.SecondaryProfession != 0 {
+		v.Check(input.SecondaryProfession.String() != data.UNDEFINED_TYPE, "secondary_profession", "must be a valid profession")
+		v.Check(input.SecondaryProfession != input.Profession, "secondary_profession", "must differ from profession")
+		v.Check(input.SecondaryLevel >= data.MINIMUM_PROFESSION_LEVEL, "secondary_level", fmt.Sprintf("must be at least %v", data.MINIMUM_PROFESSION_LEVEL))
+		v.Check(input.SecondaryLevel <= data.MAXIMUM_PROFESSION_LEVEL, "secondary_level", fmt.Sprintf("must be at most %v", data.MAXIMUM_PROFESSION_LEVEL))
+	}
 	v.Check(input.StartLevel >= data.MINIMUM_PROFESSION_LEVEL, "start_level", fmt.Sprintf("must be at least %v", data.MINIMUM_PROFESSION_LEVEL))
 	v.Check(input.FinishLevel > input.StartLevel, "finish_level", "must be greater than start_level")
 	v.Check(input.FinishLevel <= data.MAXIMUM_PROFESSION_LEVEL, "finish_level", fmt.Sprintf("must be at most %v", data.MAXIMUM_PROFESSION_LEVEL))
+	if gap, ok := input.Profession.UncoveredRange(input.StartLevel, input.FinishLevel); ok {
+		msg := fmt.Sprintf("must not cross %v-%v, where no %v recipe gives skillups and it must be levelled by gathering instead", gap[0], gap[1], input.Profession)
+		if input.StartLevel >= gap[0] {
+			v.AddError("start_level", msg)
+		} else {
+			v.AddError("finish_level", msg)
+		}
+	}
+	if input.CurrentRank != data.RANK_UNDEFINED {
+		v.Check(input.CurrentRank.String() != data.UNDEFINED_TYPE, "current_rank", "must be a valid rank")
+		v.Check(input.StartLevel <= input.CurrentRank.Cap(), "start_level", "must be within the cap of current_rank")
+	}
+	if input.CharacterLevel != 0 {
+		v.Check(input.CharacterLevel >= data.MINIMUM_CHARACTER_LEVEL, "character_level", fmt.Sprintf("must be at least %v", data.MINIMUM_CHARACTER_LEVEL))
+		v.Check(input.CharacterLevel <= data.MAXIMUM_CHARACTER_LEVEL, "character_level", fmt.Sprintf("must be at most %v", data.MAXIMUM_CHARACTER_LEVEL))
+
+		rank := data.HighestRank(input.CharacterLevel)
+		v.Check(input.FinishLevel <= rank.Cap(), "finish_level", fmt.Sprintf("can't be reached before character level %v", data.RankForSkill(input.FinishLevel).CharacterLevel()))
+	}
+	v.Check(input.Alternatives >= 0, "alternatives", "must not be negative")
+	v.Check(input.Alternatives <= MAXIMUM_ALTERNATIVES, "alternatives", fmt.Sprintf("must be at most %v", MAXIMUM_ALTERNATIVES))
+	v.Check(input.Resale == "" || validator.PermittedValue(input.Resale, []string{RESALE_MARKET_VALUE, RESALE_VENDOR}), "resale", fmt.Sprintf("must be either '%v' or '%v'", RESALE_MARKET_VALUE, RESALE_VENDOR))
+	if input.Pricing != nil {
+		input.Pricing.Validate(v)
+	}
+	for _, item := range input.Inventory {
+		v.Check(item.ID > 0, "inventory", "must only contain valid item ids")
+		v.Check(item.Quantity > 0, "inventory", "must only contain positive quantities")
+	}
+	for reputation, standing := range input.Reputation {
+		v.Check(reputation.String() != data.UNDEFINED_TYPE, "reputation", "must only contain valid factions")
+		v.Check(standing.String() != data.UNDEFINED_TYPE, "reputation", "must only contain valid standings")
+	}
+	for _, source := range input.SelfSuppliedSources {
+		v.Check(source.String() != data.UNDEFINED_TYPE, "self_supplied_sources", "must only contain valid sources")
+	}
+	if len(input.SelfSupplied) > 0 || len(input.SelfSuppliedSources) > 0 {
+		v.Check(input.FarmRate > 0, "farm_rate", "must be greater than zero when self supplying items")
+		v.Check(input.GoldPerHour > 0, "gold_per_hour", "must be greater than zero when self supplying items")
+	}
+	v.Check(input.GoldPerHour >= 0, "gold_per_hour", "must not be negative")
+	v.Check(input.Objective == "" || validator.PermittedValue(input.Objective, []string{OBJECTIVE_GOLD, OBJECTIVE_TIME}), "objective", fmt.Sprintf("must be either '%v' or '%v'", OBJECTIVE_GOLD, OBJECTIVE_TIME))
+	if input.Objective == OBJECTIVE_TIME {
+		v.Check(input.GoldPerHour > 0, "gold_per_hour", "must be greater than zero when valuing time")
+	}
+	_, err := data.ParseRecipeExport(input.KnownRecipesExport, input.Profession)
+	v.Check(err == nil, "known_recipes_export", fmt.Sprintf("must be a valid export: %v", err))
+	v.Check(input.MaxDays >= 0, "max_days", "must not be negative")
+	v.Check(input.Simulations >= 0, "simulations", "must not be negative")
+	v.Check(input.Simulations <= MAXIMUM_SIMULATIONS, "simulations", fmt.Sprintf("must be at most %v", MAXIMUM_SIMULATIONS))
 }
 
-func (app *application) recipeCost(p *data.Player, r *data.Recipe) (int, [][]int, error) {
+// recipeCost returns the cost of crafting a recipe once.
+func (pl *planner) recipeCost(r *data.Recipe) (int, error) {
 	totalCost := 0
-	reqPurchases := [][]int{}
 
 	for _, item := range r.Reagents {
-		cost, purchases, err := app.reagentCost(p, item)
+		cost, err := pl.reagentCost(item)
 		if err != nil {
-			return 0, nil, fmt.Errorf("couldn't craft item: %w", err)
+			return 0, fmt.Errorf("couldn't craft item: %w", err)
 		}
 
 		totalCost += cost
-		reqPurchases = append(reqPurchases, purchases...)
 	}
-	return totalCost, reqPurchases, nil
+	return totalCost, nil
 }
 
-func (app *application) reagentCost(p *data.Player, reagent []int) (int, [][]int, error) {
-	var (
-		ahCost    int
-		craftCost int
+// acquisition is a route by which an item can be acquired
+type acquisition int
 
-		ahList    [][]int
-		craftList [][]int
-	)
+const (
+	ACQUIRE_VENDOR acquisition = iota + 1
+	ACQUIRE_AUCTION
+	ACQUIRE_CRAFT
+	ACQUIRE_FARM
+	ACQUIRE_CONVERT
+)
 
+// reagentCost returns the cheapest cost of acquiring a reagent, provided as [itemID, quantity].
+func (pl *planner) reagentCost(reagent []int) (int, error) {
 	id, qty := reagent[0], reagent[1]
 
+	_, cost, err := pl.itemCost(id)
+	if err != nil {
+		return 0, err
+	}
+
+	return cost * qty, nil
+}
+
+// itemCost returns the cheapest route for acquiring a single unit of an item, and its cost. Routes are remembered for
+// the rest of the request so that each item is only priced once, except where crafting cycles were broken along the
+// way, as the route then depends upon what was being crafted at the time.
+func (pl *planner) itemCost(id int) (acquisition, int, error) {
+	if route, ok := pl.routes[id]; ok && (!route.cyclic || len(pl.crafting) == 0) {
+		return route.route, route.cost, route.err
+	}
+
+	cuts := pl.cuts
+	route, cost, err := pl.cheapestRoute(id)
+	if cyclic := pl.cuts != cuts; !cyclic || len(pl.crafting) == 0 {
+		pl.routes[id] = &itemRoute{route: route, cost: cost, err: err, cyclic: cyclic}
+	}
+
+	return route, cost, err
+}
+
+// cheapestRoute determines the cheapest route for acquiring a single unit of an item, and its cost.
+func (pl *planner) cheapestRoute(id int) (acquisition, int, error) {
+	var (
+		ahCost      int
+		craftCost   int
+		convertCost int
+		farmCost    int
+	)
+
+	// Items restricted to the other faction can't be acquired at all
+	if item, err := pl.stores.Items.GetByID(id); err == nil && !item.Faction.Allows(pl.player.Faction) {
+		return 0, 0, fmt.Errorf("only available to the %v", item.Faction)
+	}
+
 	// Get cost to buy from vendor (and assume vendor is always cheapest)
-	vendorItem, err := app.stores.VendorItems.GetByID(id)
-	if err == nil {
-		return vendorItem.Cost * qty, [][]int{{id, qty}}, nil
+	vendorItem, err := pl.stores.VendorItems.GetByID(id)
+	if err == nil && vendorItem.Faction.Allows(pl.player.Faction) {
+		return ACQUIRE_VENDOR, vendorItem.Cost, nil
 	}
 
 	// Get cost to craft it
-	craftCost, craftList, err = app.craftingCost(id, p)
+	craftCost, _, err = pl.craftingCost(id)
 	if err != nil {
 		craftCost = math.MaxInt
-		craftList = nil
 	}
 
-	// Get cost to buy it from the AH
-	tsmItem, err := app.tsmService.GetPrice(p.AuctionHouseID, id)
+	// Get cost to convert it from something else
+	convertCost, _, err = pl.conversionCost(id)
 	if err != nil {
+		convertCost = math.MaxInt
+	}
+
+	// Get cost of the players time to farm it
+	farmCost, err = pl.farmingCost(id)
+	if err != nil {
+		farmCost = math.MaxInt
+	}
+
+	// Get cost to buy it from the AH
+	tsmItem, err := pl.tsmService.GetPrice(pl.player.AuctionHouseID, id)
+	if err != nil || tsmItem.MinBuyout <= 0 {
 		ahCost = math.MaxInt
-		ahList = nil
 	} else {
-		// TODO: Make this configurable?
-		ahCost = tsmItem.MinBuyout
-		ahList = append(ahList, []int{id, qty})
+		ahCost = pl.auctionPrice(tsmItem)
 	}
 
-	// Determine cheapest route
+	// Determine cheapest route, farming whenever the player is willing to and it's no more expensive
 	switch {
-	case ahCost < craftCost:
-		return ahCost, ahList, nil
-	case craftCost < ahCost:
-		return craftCost, craftList, nil
-	case craftCost == ahCost && (craftCost == math.MaxInt || ahCost == math.MaxInt):
-		return 0, nil, errors.New("couldn't buy or craft this reagent")
+	case farmCost != math.MaxInt && farmCost <= craftCost && farmCost <= convertCost && farmCost <= ahCost:
+		return ACQUIRE_FARM, farmCost, nil
+	case craftCost < ahCost && craftCost <= convertCost:
+		return ACQUIRE_CRAFT, craftCost, nil
+	case convertCost < ahCost:
+		return ACQUIRE_CONVERT, convertCost, nil
+	case ahCost == math.MaxInt:
+		return 0, 0, errors.New("couldn't buy, craft, convert or farm this reagent")
 	default:
-		// TODO: Refactor(?)
-		return ahCost, ahList, nil
+		return ACQUIRE_AUCTION, ahCost, nil
 	}
 }
 
-func (app *application) craftingCost(itemID int, p *data.Player) (int, [][]int, error) {
-	recipeID, err := app.stores.Items.GetCraftingRecipeID(itemID)
-	if err != nil {
-		return math.MaxInt, nil, fmt.Errorf("couldn't get crafting cost: %w", err)
+// ErrCyclicCraft is returned when crafting an item would require crafting that same item further down the chain of
+// reagents, e.g. transmuting Eternal Fire from Eternal Life which was itself transmuted from Eternal Fire.
+var ErrCyclicCraft = errors.New("crafting this item requires itself")
+
+// craftingCost returns the cost of crafting a single unit of an item, and the cheapest of the recipes creating it that
+// the player is able to craft. Recipes creating more than one of an item have their cost spread over the expected
+// quantity created. Items already being crafted further up the chain of reagents can't be crafted again, so that cycles
+// are broken and each reagent must be acquired some other way.
+func (pl *planner) craftingCost(itemID int) (int, *data.Recipe, error) {
+	if pl.crafting[itemID] {
+		pl.cuts++
+		return math.MaxInt, nil, ErrCyclicCraft
 	}
 
-	recipe, err := app.stores.Recipes.GetByID(recipeID)
+	pl.crafting[itemID] = true
+	defer delete(pl.crafting, itemID)
 
-	switch {
-	case err != nil:
-		return math.MaxInt, nil, fmt.Errorf("couldn't get recipe: %w", err)
-	case len(recipe.Profession) <= 0 && recipe.Profession[0] != p.Profession:
-		return math.MaxInt, nil, errors.New("can't craft recipe with this profession")
-	case strings.Contains(recipe.Name, "Transmute"):
-		return math.MaxInt, nil, errors.New("avoid cyclical transmutes")
+	var (
+		cheapest = math.MaxInt
+		recipe   *data.Recipe
+	)
+
+	for _, r := range pl.stores.Recipes.GetByCreated(itemID) {
+		if !pl.craftable(r) {
+			continue
+		}
+
+		cost, err := pl.recipeCost(r)
+		if err != nil {
+			continue
+		}
+
+		if cost = int(math.Ceil(float64(cost) / r.Yield())); cost < cheapest {
+			cheapest, recipe = cost, r
+		}
 	}
 
-	cost, items, err := app.recipeCost(p, recipe)
-	if err != nil {
-		return math.MaxInt, nil, fmt.Errorf("couldn't get recipe cost: %w", err)
+	if recipe == nil {
+		return math.MaxInt, nil, errors.New("can't craft this item with the players professions")
 	}
 
-	return cost, items, nil
+	return cheapest, recipe, nil
+}
+
+// craftable returns whether the player is able to craft a recipe to create its item at the step being priced.
+func (pl *planner) craftable(r *data.Recipe) bool {
+	if len(r.Profession) > 0 && r.Profession[0] == pl.player.Profession {
+		pl.dependsOn(r.RequiredSkill())
+	}
+	return pl.player.CanCraft(r, pl.skill) && r.Yield() > 0 && pl.remainingCrafts(r) > 0
 }
 
-func (app *application) getRequiredCrafts(p *data.Player, r *data.Recipe) int {
-	// Chance of success
-	chance := float64(r.Colors[data.ColorGrey]-p.SkillCurrent) / float64(r.Colors[data.ColorGrey]-r.Colors[data.ColorYellow])
+// getRequiredCrafts returns the (conservative) number of times a recipe must be crafted at a given skill level to gain
+// a skillup.
+func (pl *planner) getRequiredCrafts(skill int, r *data.Recipe) int {
+	chance := r.SkillupChance(skill)
 
 	if chance >= 1 {
 		return 1
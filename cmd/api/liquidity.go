@@ -0,0 +1,66 @@
+package main
+
+import (
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/data"
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/tsm"
+)
+
+// auctionPrice returns the price of buying a single unit of an item from the auction house using the requested pricing
+// strategy, falling back to the minimum buyout where the strategy has no data. Items the auction house can't supply
+// enough of are priced at their market value when that is higher, as the cheapest auctions will run out long before
+// the plan is complete.
+func (pl *planner) auctionPrice(item *tsm.TSMItemRes) int {
+	price := pl.pricing.Price(item)
+	if price <= 0 {
+		price = item.MinBuyout
+	}
+
+	if _, ok := pl.illiquid[item.ItemID]; ok && item.MarketValue > price {
+		price = item.MarketValue
+	}
+
+	return price
+}
+
+// shortfalls compares the auction house purchases of a plan against the number of auctions currently live for each
+// item, returning how many of each item can't be bought. Each auction is conservatively assumed to be for a single
+// item.
+func (pl *planner) shortfalls(plan *data.Plan) map[int]int {
+	res := make(map[int]int)
+
+	for _, v := range plan.ShoppingList.AuctionHouse {
+		tsmItem, err := pl.tsmService.GetPrice(pl.player.AuctionHouseID, v.ID)
+		if err != nil {
+			continue
+		}
+
+		if v.Quantity > tsmItem.NumAuctions {
+			res[v.ID] = v.Quantity - tsmItem.NumAuctions
+		}
+	}
+
+	return res
+}
+
+// flagShortfalls records on the plan which items the auction house can't supply enough of, and marks each step that
+// buys them as requiring some farming.
+func (pl *planner) flagShortfalls(plan *data.Plan) {
+	shortfalls := pl.shortfalls(plan)
+	if len(shortfalls) == 0 {
+		return
+	}
+
+	for _, v := range plan.ShoppingList.AuctionHouse {
+		if qty, ok := shortfalls[v.ID]; ok {
+			plan.Shortfalls = append(plan.Shortfalls, data.PurchaseItem{ID: v.ID, Quantity: qty})
+		}
+	}
+
+	for i := range plan.Steps {
+		for _, v := range plan.Steps[i].Purchases.AuctionHouse {
+			if _, ok := shortfalls[v.ID]; ok {
+				plan.Steps[i].FarmRequired = append(plan.Steps[i].FarmRequired, v.ID)
+			}
+		}
+	}
+}
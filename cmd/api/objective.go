@@ -0,0 +1,57 @@
+package main
+
+import (
+	"math"
+
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/data"
+)
+
+// Objectives the planner can minimise
+const (
+	OBJECTIVE_GOLD string = "gold"
+	OBJECTIVE_TIME string = "time"
+)
+
+// objective scores each craft considered by the planner, which then chooses the sequence of recipes with the lowest
+// total score. One-off costs such as learning a recipe are always scored at their cost in gold.
+type objective interface {
+	score(r *data.Recipe, craftCost int) int
+}
+
+// goldObjective scores a craft by the gold it costs, for players who want the cheapest plan.
+type goldObjective struct{}
+
+func (goldObjective) score(r *data.Recipe, craftCost int) int {
+	return craftCost
+}
+
+// timeObjective scores a craft by the gold it costs plus the value of the time spent casting it, for players who'd
+// rather spend more gold on fewer crafts.
+type timeObjective struct {
+	goldPerHour int
+}
+
+func (o timeObjective) score(r *data.Recipe, craftCost int) int {
+	return craftCost + int(math.Round(r.CastDuration().Hours()*float64(o.goldPerHour*COPPER_PER_GOLD)))
+}
+
+// newObjective returns the objective requested, defaulting to the cheapest plan.
+func newObjective(input *plRequestPayload) objective {
+	switch input.Objective {
+	case OBJECTIVE_TIME:
+		return timeObjective{goldPerHour: input.GoldPerHour}
+	default:
+		return goldObjective{}
+	}
+}
+
+// reportCasting records on the plan how long the player is expected to spend casting the crafts of each step.
+func (pl *planner) reportCasting(plan *data.Plan) {
+	for _, step := range plan.Steps {
+		recipe, err := pl.stores.Recipes.GetByID(step.RecipeID)
+		if err != nil {
+			continue
+		}
+		plan.CastingHours += float64(step.Crafts) * recipe.CastDuration().Hours()
+	}
+}
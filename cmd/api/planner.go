@@ -0,0 +1,816 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/data"
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/tsm"
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/validator"
+)
+
+// planner holds everything relating to a single levelling request, such as the player being levelled and the filters
+// and options they've chosen, so that it's available throughout the planning and pricing of their plan.
+type planner struct {
+	*application
+
+	input     *plRequestPayload
+	server    *data.Server
+	player    *data.Player
+	rank      data.Rank
+	fSource   *data.FilterSource
+	fSkillup  *data.FilterSkillup
+	fLevel    *data.FilterLevel
+	fRep      *data.FilterReputation
+	pricing   *tsm.PricingStrategy
+	objective objective
+	quotes    map[int]*recipeQuote
+	routes    map[int]*itemRoute        // itemID -> cheapest route of acquiring it
+	teaching  map[int]*recipeItemQuote  // itemID -> price of buying an item teaching a recipe
+	levels    map[int]int               // itemID -> character level required to use an item teaching a recipe
+	illiquid  map[int]int               // itemID -> quantity the auction house is short of
+	owned     map[int]int               // itemID -> quantity of the players own items not yet used
+	crafting  map[int]bool              // itemIDs currently being crafted or converted, used to detect cycles
+	cuts      int                       // number of times a crafting cycle has been broken
+	cooldowns map[string]*data.Cooldown // name -> crafts made subject to each cooldown
+	skill     int                       // skill level of the step being priced
+	pricedAt  [2]int                    // effective skill levels from and before which the remembered prices hold
+
+	alternatives [][]segment // sequences of recipes doing without one of those planned, found when planning
+}
+
+// newPlanner returns a planner for the provided levelling request.
+func (app *application) newPlanner(input *plRequestPayload, server *data.Server) *planner {
+	pl := &planner{
+		application: app,
+		input:       input,
+		server:      server,
+		fSkillup:    data.NewFilterSkillup(input.FilterSkillup),
+		pricing:     input.Pricing,
+		objective:   newObjective(input),
+		illiquid:    make(map[int]int),
+		crafting:    make(map[int]bool),
+	}
+	if pl.pricing == nil {
+		pl.pricing = tsm.NewPricingStrategy(tsm.PRICE_MIN_BUYOUT)
+	}
+	pl.reset()
+
+	return pl
+}
+
+// reset clears everything learned while building a plan, so that the planner can plan again from scratch.
+func (pl *planner) reset() {
+	// maintain a player to remember known recipes and inventory items used for future crafts
+	pl.player = data.NewPlayer(pl.input.Profession, pl.input.StartLevel, pl.input.FinishLevel, pl.server.AuctionHouseID(pl.input.Faction))
+	pl.player.SecondaryProfession = pl.input.SecondaryProfession
+	pl.player.Race = pl.input.Race
+	pl.player.Faction = pl.input.Faction
+	pl.rank = pl.currentRank()
+	pl.player.SkillSecondary = pl.input.SecondaryLevel
+	pl.skill = pl.input.StartLevel
+	pl.quotes = make(map[int]*recipeQuote)
+	pl.routes = make(map[int]*itemRoute)
+	pl.pricedAt = [2]int{0, math.MaxInt}
+	pl.teaching = make(map[int]*recipeItemQuote)
+	pl.levels = make(map[int]int)
+	pl.cooldowns = make(map[string]*data.Cooldown)
+	pl.fSource = data.NewFilterSource(pl.input.FilterSource, pl.player.Recipes)
+	pl.fLevel = data.NewFilterLevel(pl.input.CharacterLevel, pl.player.Recipes, pl.requiredLevel)
+	pl.fRep = data.NewFilterReputation(pl.input.Faction, pl.input.Reputation, pl.player.Recipes)
+
+	// start with whatever recipes the player already knows, whether listed or exported from the game
+	for _, id := range pl.input.KnownRecipes {
+		pl.player.AddRecipe(id)
+	}
+	exported, _ := data.ParseRecipeExport(pl.input.KnownRecipesExport, pl.input.Profession)
+	for _, id := range exported {
+		pl.player.AddRecipe(id)
+	}
+
+	// start with whatever the player already owns
+	pl.owned = make(map[int]int)
+	for _, v := range pl.input.Inventory {
+		pl.player.AddInventory(v.ID, v.Quantity)
+		pl.owned[v.ID] += v.Quantity
+	}
+}
+
+// plan determines the cheapest sequence of recipes and builds the resulting plan.
+func (pl *planner) plan() (*data.Plan, error) {
+	paths, err := pl.planPaths(1)
+	if err != nil {
+		return nil, err
+	}
+	pl.alternatives = pl.alternativePaths(paths[0], pl.input.Alternatives)
+
+	return pl.buildPlan(paths[0])
+}
+
+// recipeQuote is the net cost of crafting a recipe once after crediting any resale value.
+type recipeQuote struct {
+	cost   int
+	resale int
+	err    error
+}
+
+// itemRoute is the cheapest route of acquiring a single unit of an item. Cyclic routes were found by breaking a
+// crafting cycle, so only hold true when nothing else is being crafted.
+type itemRoute struct {
+	route  acquisition
+	cost   int
+	err    error
+	cyclic bool
+}
+
+// segment is a run of a single recipe over a contiguous range of skill levels, from start up to (but not including)
+// end.
+type segment struct {
+	recipe    data.Recipe
+	start     int
+	end       int
+	crafts    int
+	craftCost int
+	resale    int
+}
+
+// planLink is one of the cheapest known ways of arriving at a skill level, following the link at index prev of the
+// skill level it came from. Links with the same key arrived by the same sequence of recipes, counting recipes made from
+// the same reagents as one.
+type planLink struct {
+	cost     int
+	from     int
+	prev     int
+	key      int
+	recipe   data.Recipe
+	reagents string
+	quote    *recipeQuote
+	crafts   int
+}
+
+// sequenceKey identifies a sequence of recipes by the key of the sequence it extends and the reagents of the recipe
+// extending it.
+type sequenceKey struct {
+	prev     int
+	reagents string
+}
+
+// candidate is a recipe able to provide skillups at a skill level, along with what it costs to learn and craft.
+type candidate struct {
+	recipe    data.Recipe
+	quote     *recipeQuote
+	learnCost int
+}
+
+// candidates returns every recipe able to provide the player skillups at a skill level, excluding any that can't be
+// priced.
+func (pl *planner) candidates(skill int) []candidate {
+	var res []candidate
+
+	pl.priceAt(skill)
+
+	bonus := pl.player.SkillBonus(pl.player.Profession)
+	recipes := pl.stores.Recipes.GetFiltered(skill+bonus, pl.player.Profession, pl.player.Faction, pl.fSource, pl.fSkillup, pl.fLevel, pl.fRep)
+	for _, recipe := range recipes {
+		quote := pl.quote(&recipe)
+
+		switch {
+		case errors.Is(quote.err, tsm.ErrIsBlacklisted):
+			// skip over this candidate because it has items we can't determine a cost for
+			continue
+		case quote.err != nil:
+			pl.logger.Debugf("unable to determine crafting cost of %v: %v", recipe.ID, quote.err)
+			continue
+		}
+
+		learnCost, _, err := pl.learningCost(&recipe)
+		if err != nil {
+			pl.logger.Debugf("unable to determine learning cost of %v: %v", recipe.ID, err)
+			continue
+		}
+
+		res = append(res, candidate{recipe: recipe, quote: quote, learnCost: learnCost})
+	}
+
+	return res
+}
+
+// run evaluates crafting a candidate from a skill level for as long as it still provides skillups, up to a maximum
+// number of crafts, calling visit with the crafts made and their score each time another skill level is reached. Reagents the
+// player owns are scored at what they're worth to the player rather than what they'd cost to acquire.
+func (pl *planner) run(c *candidate, skill, finish, maxCrafts int, visit func(level, crafts, score int)) {
+	bonus := pl.player.SkillBonus(pl.player.Profession)
+	crafts, score := 0, 0
+
+	for level := skill; level < finish && pl.fSkillup.Filter(&c.recipe, level+bonus); level++ {
+		numCrafts := pl.getRequiredCrafts(level+bonus, &c.recipe)
+		if crafts+numCrafts > maxCrafts {
+			break
+		}
+		score += numCrafts*pl.objective.score(&c.recipe, c.quote.cost) - pl.ownedSaving(&c.recipe, crafts, crafts+numCrafts)
+		crafts += numCrafts
+
+		visit(level+1, crafts, score)
+	}
+}
+
+// planPaths determines the cheapest sequences of recipes taking the player from their current skill to their desired
+// skill, returning up to the provided number of them, cheapest first. Rather than picking the cheapest recipe at each
+// skill level in isolation, every candidate is evaluated over every run of skill levels it remains craftable for, and
+// the cheapest combinations of runs are chosen by dynamic programming over skill levels. This accounts for the fixed
+// cost of learning a recipe being spread over the whole run, and for the colour of a recipe (and so the crafts
+// required) changing along the way. Colours are judged by the players effective skill, i.e. including any racial
+// bonus. A recipe is only charged for learning the first time a sequence uses it, so what a run costs depends on the
+// way the skill level it starts from was reached, and several of the cheapest ways of reaching each skill level are
+// kept even when only the cheapest sequence is wanted. Each sequence returned uses a different series of recipes,
+// rather than merely changing recipe at a different skill level or swapping a recipe for another made from the same
+// reagents, and does without at least one of the recipes used by each sequence returned before it.
+func (pl *planner) planPaths(k int) ([][]segment, error) {
+	return pl.searchPaths(k, pl.candidates)
+}
+
+// searchPaths performs the search for planPaths, taking the candidates available at each skill level from the provided
+// function.
+func (pl *planner) searchPaths(k int, candidates func(skill int) []candidate) ([][]segment, error) {
+	start, finish := pl.player.SkillCurrent, pl.player.SkillDesired
+
+	// search beyond those returned, both for runs reusing a recipe learned along a dearer way and to make up for any
+	// sequences too similar to another
+	width := k * SEARCH_WIDTH
+
+	// best[i] holds the cheapest ways found of reaching skill level start+i, cheapest first
+	best := make([][]planLink, finish-start+1)
+	best[0] = []planLink{{cost: 0}}
+
+	// each distinct sequence of recipes is given the next key
+	sequences := make(map[sequenceKey]int)
+
+	for skill := start; skill < finish; skill++ {
+		links := best[skill-start]
+		if len(links) == 0 {
+			continue
+		}
+
+		for _, c := range candidates(skill) {
+			c := c
+			reagents := reagentsKey(&c.recipe)
+
+			// a recipe already used on the way here has already been learned, and its cooldown may be partly spent
+			learnCosts, remaining, maxCrafts := make([]int, len(links)), make([]int, len(links)), 0
+			keys := make([]int, len(links))
+			for i, link := range links {
+				// recipes made from the same reagents are interchangeable, so a run of them is keyed as one
+				keys[i] = link.key
+				if link.reagents != reagents {
+					next := sequenceKey{prev: link.key, reagents: reagents}
+					if _, ok := sequences[next]; !ok {
+						sequences[next] = len(sequences) + 1
+					}
+					keys[i] = sequences[next]
+				}
+
+				if !usedOnPath(best, start, skill, i, c.recipe.ID) {
+					learnCosts[i] = c.learnCost
+				}
+				remaining[i] = pl.remainingCrafts(&c.recipe)
+				if c.recipe.Cooldown > 0 {
+					remaining[i] -= cooldownOnPath(best, start, skill, i, c.recipe.CooldownName())
+				}
+				maxCrafts = validator.Max(maxCrafts, remaining[i])
+			}
+
+			pl.run(&c, skill, finish, maxCrafts, func(level, crafts, score int) {
+				for i, link := range links {
+					// a run of the same recipe is evaluated as a single segment from where it began, so don't split it
+					if link.recipe.ID == c.recipe.ID || crafts > remaining[i] {
+						continue
+					}
+
+					pl.insertLink(&best[level-start], width, planLink{
+						cost:     link.cost + learnCosts[i] + score,
+						from:     skill,
+						prev:     i,
+						key:      keys[i],
+						recipe:   c.recipe,
+						reagents: reagents,
+						quote:    c.quote,
+						crafts:   crafts,
+					})
+				}
+			})
+		}
+	}
+
+	if len(best[finish-start]) == 0 {
+		reached := start
+		for i := range best {
+			if len(best[i]) > 0 {
+				reached = start + i
+			}
+		}
+		if level := pl.blockedAt(reached + pl.player.SkillBonus(pl.player.Profession)); level > 0 {
+			return nil, &blockedError{Skill: reached, CharacterLevel: level}
+		}
+		return nil, fmt.Errorf("unable to find a suitable craft for %v -> %v", reached, reached+1)
+	}
+
+	// walk back from the desired skill to build each plan
+	var paths [][]segment
+	for i := range best[finish-start] {
+		if len(paths) == k {
+			break
+		}
+
+		var segments []segment
+		for skill, index := finish, i; skill > start; {
+			link := best[skill-start][index]
+			segments = append([]segment{{
+				recipe:    link.recipe,
+				start:     link.from,
+				end:       skill,
+				crafts:    link.crafts,
+				craftCost: link.quote.cost,
+				resale:    link.quote.resale,
+			}}, segments...)
+			skill, index = link.from, link.prev
+		}
+
+		// a sequence using every recipe of another offers no way of doing without any of them
+		distinct := true
+		for _, path := range paths {
+			if !avoids(segments, path) {
+				distinct = false
+				break
+			}
+		}
+		if !distinct {
+			continue
+		}
+
+		paths = append(paths, segments)
+	}
+
+	return paths, nil
+}
+
+// avoids returns whether a sequence of recipes does without at least one of the recipes used by another, counting
+// recipes made from the same reagents as one.
+func avoids(a, b []segment) bool {
+	used := make(map[string]bool)
+	for _, seg := range a {
+		used[reagentsKey(&seg.recipe)] = true
+	}
+
+	for _, seg := range b {
+		if !used[reagentsKey(&seg.recipe)] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reagentsKey identifies the reagents a recipe is made from, so that recipes made from the same reagents are known to
+// be interchangeable. Recipes made without reagents are identified by their own ID.
+func reagentsKey(r *data.Recipe) string {
+	if len(r.Reagents) == 0 {
+		return fmt.Sprintf("#%v", r.ID)
+	}
+
+	reagents := make([]string, 0, len(r.Reagents))
+	for _, v := range r.Reagents {
+		reagents = append(reagents, fmt.Sprintf("%vx%v", v[0], v[1]))
+	}
+	sort.Strings(reagents)
+
+	return strings.Join(reagents, ",")
+}
+
+// usedOnPath returns whether a recipe was used on the way to the link at an index of a skill level, by walking back
+// through the links leading to it.
+func usedOnPath(best [][]planLink, start, skill, index, recipeID int) bool {
+	for skill > start {
+		link := best[skill-start][index]
+		if link.recipe.ID == recipeID {
+			return true
+		}
+		skill, index = link.from, link.prev
+	}
+
+	return false
+}
+
+// SEARCH_WIDTH is how many ways of reaching each skill level are searched for each sequence of recipes returned.
+const SEARCH_WIDTH int = 5
+
+// insertLink adds a way of arriving at a skill level to those already found, keeping only the provided number of the
+// cheapest. Of the ways arriving by the same sequence of recipes, only the preferred is kept.
+func (pl *planner) insertLink(links *[]planLink, k int, link planLink) {
+	for i, v := range *links {
+		if v.key != link.key {
+			continue
+		}
+		if !pl.preferred(link.cost, &link.recipe, &v) {
+			return
+		}
+		*links = append((*links)[:i], (*links)[i+1:]...)
+		break
+	}
+
+	pos := len(*links)
+	for i := range *links {
+		if pl.preferred(link.cost, &link.recipe, &(*links)[i]) {
+			pos = i
+			break
+		}
+	}
+	if pos >= k {
+		return
+	}
+
+	*links = append(*links, planLink{})
+	copy((*links)[pos+1:], (*links)[pos:])
+	(*links)[pos] = link
+	if len(*links) > k {
+		*links = (*links)[:k]
+	}
+}
+
+// buildPlan follows the provided segments in order, acquiring the reagents for each from the players inventory before
+// buying or crafting whatever else is required. Anything crafted by a step is added to the inventory so that later
+// steps can make use of it, rather than pricing intermediate reagents twice. Training the next rank of the profession
+// is added as a step of its own wherever a segment would take the player beyond their current rank, splitting the
+// segment at the skill level the rank becomes available.
+func (pl *planner) buildPlan(segments []segment) (*data.Plan, error) {
+	plan := data.NewPlan(pl.input.Profession, pl.input.StartLevel, pl.input.FinishLevel)
+	plan.SkillBonus = pl.player.SkillBonus(pl.player.Profession)
+
+	segments = pl.splitForTraining(segments)
+
+	// reagents can only be crafted with whatever the steps leave of a cooldown
+	for _, seg := range segments {
+		pl.recordCooldown(&seg.recipe, seg.crafts)
+	}
+
+	for _, seg := range segments {
+		pl.priceAt(seg.start)
+		pl.train(plan, seg.start, seg.end)
+
+		pl.logger.Debugf("%v -> %v: %v (%v) crafted %v times at %v each", seg.start, seg.end, seg.recipe.Name, seg.recipe.ID, seg.crafts, intToGold(seg.craftCost))
+
+		// the recipe must be learned the first time it's used, after which it's known
+		learnCost, recipeItemID, err := pl.learningCost(&seg.recipe)
+		if err != nil {
+			return nil, err
+		}
+		note := pl.reputationNote(&seg.recipe)
+		runnerUps := pl.runnerUps(seg, learnCost)
+		pl.player.AddRecipe(seg.recipe.ID)
+
+		// an item teaching the recipe is bought along with the reagents, whereas a trainer's fee is paid directly
+		order, trainingFee := data.NewPurchaseOrder(), learnCost
+		if recipeItemID != 0 {
+			if _, vendor, _ := pl.recipeItemPrice(recipeItemID); vendor {
+				order.AddVendor(recipeItemID, 1, learnCost)
+			} else {
+				order.AddAuction(recipeItemID, 1, learnCost)
+			}
+			trainingFee = 0
+		}
+		for _, reagent := range seg.recipe.Reagents {
+			if err := pl.acquire(reagent[0], reagent[1]*seg.crafts, order); err != nil {
+				return nil, fmt.Errorf("couldn't acquire reagents for %v: %w", seg.recipe.Name, err)
+			}
+		}
+
+		// keep what was crafted for later steps, unless it's being resold
+		if created := seg.recipe.CreatedItem(); pl.input.Resale == "" && created != 0 {
+			pl.player.AddInventory(created, int(float64(seg.crafts)*seg.recipe.Yield()))
+		}
+
+		resale := seg.crafts * seg.resale
+		plan.AddStep(data.PlanStep{
+			SkillStart:   seg.start,
+			SkillEnd:     seg.end,
+			RecipeID:     seg.recipe.ID,
+			RecipeName:   seg.recipe.Name,
+			RecipeItemID: recipeItemID,
+			Crafts:       seg.crafts,
+			CraftCost:    seg.craftCost + seg.resale,
+			LearnCost:    learnCost,
+			ResaleValue:  resale,
+			Cost:         order.Cost + order.OpportunityCost + order.FarmCost + trainingFee - resale,
+			Purchases:    order,
+			Note:         note,
+			RunnerUps:    runnerUps,
+		})
+	}
+
+	pl.reportInventory(plan)
+	pl.reportFarming(plan)
+	pl.reportCooldowns(plan)
+	pl.reportCasting(plan)
+
+	return plan, nil
+}
+
+// acquire adds a quantity of an item to the purchase order, taking as much as possible from the players inventory and
+// acquiring the rest by the cheapest route, whether that's buying, crafting, converting or farming it. Crafted and
+// converted items have whatever they're made from acquired in the same way, and any extras created are added to the
+// inventory.
+func (pl *planner) acquire(id, qty int, order *data.PurchaseOrder) error {
+	if held := validator.Min(pl.player.CheckInventory(id), qty); held > 0 {
+		pl.player.ConsumeInventory(id, held)
+		order.AddFromInventory(id, held, pl.opportunityCost(id, held))
+		qty -= held
+	}
+
+	if qty == 0 {
+		return nil
+	}
+
+	route, cost, err := pl.itemCost(id)
+	if err != nil {
+		return err
+	}
+
+	return pl.acquireBy(route, cost, id, qty, order)
+}
+
+// acquireBy adds a quantity of an item acquired by the provided route to the purchase order. Crafts subject to a
+// cooldown are limited to what remains of it, with the rest acquired by the next cheapest route.
+func (pl *planner) acquireBy(route acquisition, cost, id, qty int, order *data.PurchaseOrder) error {
+	switch route {
+	case ACQUIRE_VENDOR:
+		order.AddVendor(id, qty, cost*qty)
+	case ACQUIRE_AUCTION:
+		order.AddAuction(id, qty, cost*qty)
+	case ACQUIRE_FARM:
+		order.AddFarmed(id, qty, cost*qty)
+	case ACQUIRE_CONVERT:
+		return pl.acquireConverted(id, qty, order)
+	case ACQUIRE_CRAFT:
+		cost, recipe, err := pl.craftingCost(id)
+		if err != nil {
+			return pl.acquireOtherwise(id, qty, order)
+		}
+
+		crafts := int(math.Ceil(float64(qty) / recipe.Yield()))
+		if remaining := pl.remainingCrafts(recipe); crafts > remaining {
+			made := validator.Min(int(float64(remaining)*recipe.Yield()), qty)
+			if err := pl.acquireBy(ACQUIRE_CRAFT, cost, id, made, order); err != nil {
+				return err
+			}
+			return pl.acquireOtherwise(id, qty-made, order)
+		}
+
+		// the item can't be crafted again while acquiring its own reagents, otherwise a cycle would never end
+		pl.recordCooldown(recipe, crafts)
+		pl.crafting[id] = true
+		for _, reagent := range recipe.Reagents {
+			if err := pl.acquire(reagent[0], reagent[1]*crafts, order); err != nil {
+				delete(pl.crafting, id)
+				return err
+			}
+		}
+		delete(pl.crafting, id)
+
+		created := int(float64(crafts) * recipe.Yield())
+		order.AddCrafted(id, created, cost*created)
+		pl.player.AddInventory(id, created-qty)
+	}
+
+	return nil
+}
+
+// acquireOtherwise adds a quantity of an item to the purchase order after the route found for it can no longer be
+// taken, e.g. because the cooldown of the recipe crafting it has been used up.
+func (pl *planner) acquireOtherwise(id, qty int, order *data.PurchaseOrder) error {
+	if qty <= 0 {
+		return nil
+	}
+
+	route, cost, err := pl.cheapestRoute(id)
+	if err != nil {
+		return err
+	}
+
+	return pl.acquireBy(route, cost, id, qty, order)
+}
+
+// opportunityCost returns the value of the players own items being used from the inventory, which is nothing unless
+// the player has asked for them to be valued at what they'd sell for. The players own items are assumed to be used
+// before anything crafted along the way.
+func (pl *planner) opportunityCost(id, qty int) int {
+	used := validator.Min(pl.owned[id], qty)
+	pl.owned[id] -= used
+
+	if used == 0 {
+		return 0
+	}
+
+	return pl.ownedPrice(id) * used
+}
+
+// ownedPrice returns the value of a single unit of the players own item, which is nothing unless the player has asked
+// for them to be valued at what they'd sell for.
+func (pl *planner) ownedPrice(id int) int {
+	if !pl.input.OwnedOpportunityCost {
+		return 0
+	}
+
+	tsmItem, err := pl.tsmService.GetPrice(pl.player.AuctionHouseID, id)
+	if err != nil {
+		return 0
+	}
+
+	return pl.auctionPrice(tsmItem)
+}
+
+// ownedSaving returns how much cheaper the players own items make the crafts of a recipe between two numbers of crafts,
+// compared to acquiring every reagent. Each run of a recipe is assumed to have the players items to itself.
+func (pl *planner) ownedSaving(r *data.Recipe, from, to int) int {
+	saving := 0
+	for _, reagent := range r.Reagents {
+		id := reagent[0]
+		used := validator.Min(pl.owned[id], reagent[1]*to) - validator.Min(pl.owned[id], reagent[1]*from)
+		if used <= 0 {
+			continue
+		}
+
+		_, cost, err := pl.itemCost(id)
+		if err != nil {
+			continue
+		}
+		saving += validator.Max(cost-pl.ownedPrice(id), 0) * used
+	}
+
+	return saving
+}
+
+// reportInventory records on the plan which of the players own items were used, and what is left in the inventory
+// once the plan is complete.
+func (pl *planner) reportInventory(plan *data.Plan) {
+	for _, v := range pl.input.Inventory {
+		if used := v.Quantity - pl.owned[v.ID]; used > 0 {
+			plan.OwnedUsed = append(plan.OwnedUsed, data.PurchaseItem{ID: v.ID, Quantity: used})
+		}
+	}
+
+	for id, qty := range pl.player.Inventory {
+		if qty > 0 {
+			plan.Leftovers = append(plan.Leftovers, data.PurchaseItem{ID: id, Quantity: qty})
+		}
+	}
+	sort.Slice(plan.Leftovers, func(i, j int) bool {
+		return plan.Leftovers[i].ID < plan.Leftovers[j].ID
+	})
+}
+
+// preferred returns whether arriving at a skill level with the provided recipe and cost is preferable to the cheapest
+// way found so far. When the costs are tied, recipes the player already knows are preferred.
+func (pl *planner) preferred(cost int, r *data.Recipe, current *planLink) bool {
+	if cost != current.cost {
+		return cost < current.cost
+	}
+
+	return pl.player.HasRecipe(r.ID) && !pl.player.HasRecipe(current.recipe.ID)
+}
+
+// priceAt sets the skill level of the step being priced. Which reagents the player can craft or convert with the
+// profession being levelled depends upon their skill, so prices remembered at another skill level are forgotten unless
+// the same recipes and conversions were within reach.
+func (pl *planner) priceAt(skill int) {
+	pl.skill = skill
+
+	effective := skill + pl.player.SkillBonus(pl.player.Profession)
+	if effective >= pl.pricedAt[0] && effective < pl.pricedAt[1] {
+		return
+	}
+
+	pl.quotes = make(map[int]*recipeQuote)
+	pl.routes = make(map[int]*itemRoute)
+	pl.pricedAt = [2]int{0, math.MaxInt}
+}
+
+// dependsOn records that the prices being determined depend upon whether the player has reached a skill level in the
+// profession being levelled, so that they're only remembered for as long as that remains the case.
+func (pl *planner) dependsOn(required int) {
+	if required <= pl.skill+pl.player.SkillBonus(pl.player.Profession) {
+		pl.pricedAt[0] = validator.Max(pl.pricedAt[0], required)
+	} else {
+		pl.pricedAt[1] = validator.Min(pl.pricedAt[1], required)
+	}
+}
+
+// quote returns the net cost of crafting a recipe once, pricing it on first use.
+func (pl *planner) quote(r *data.Recipe) *recipeQuote {
+	if quote, ok := pl.quotes[r.ID]; ok {
+		return quote
+	}
+
+	cost, err := pl.recipeCost(r)
+	quote := &recipeQuote{cost: cost, err: err}
+	if err == nil {
+		quote.resale = pl.resaleValue(r)
+		quote.cost -= quote.resale
+	}
+	pl.quotes[r.ID] = quote
+
+	return quote
+}
+
+// learningCost returns the one-off cost of learning a recipe, and the ID of the item that teaches it if one must be
+// bought. Recipes the player already knows are free, trainer recipes cost their training fee, and recipes taught by an
+// item cost whatever the cheapest of those items can be bought for. Recipes without either (e.g. discoveries, quest
+// rewards or those known automatically) are free.
+func (pl *planner) learningCost(r *data.Recipe) (int, int, error) {
+	if pl.player.HasRecipe(r.ID) {
+		return 0, 0, nil
+	}
+
+	if r.TrainingCost > 0 || validator.PermittedValue(data.SOURCE_TRAINER, r.Source) {
+		return r.TrainingCost, 0, nil
+	}
+
+	itemIDs := pl.stores.Items.GetRecipeItemIDs(r.ID)
+	if len(itemIDs) == 0 {
+		return 0, 0, nil
+	}
+
+	cheapest, cheapestID, lastErr := math.MaxInt, 0, error(nil)
+	for _, id := range itemIDs {
+		if item, err := pl.stores.Items.GetByID(id); err == nil && !item.Faction.Allows(pl.player.Faction) {
+			continue
+		}
+
+		cost, _, err := pl.recipeItemPrice(id)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if cost < cheapest {
+			cheapest, cheapestID = cost, id
+		}
+	}
+
+	if cheapest == math.MaxInt {
+		if lastErr != nil {
+			return 0, 0, fmt.Errorf("couldn't buy an item teaching recipe %v: %w", r.ID, lastErr)
+		}
+		return 0, 0, fmt.Errorf("couldn't buy an item teaching recipe %v", r.ID)
+	}
+
+	return cheapest, cheapestID, nil
+}
+
+// ErrUnknownVendorPrice is returned for an item sold by vendors when the price they sell it for isn't known.
+var ErrUnknownVendorPrice = errors.New("unknown vendor price")
+
+// recipeItemQuote is the price of buying an item teaching a recipe, and whether it's bought from a vendor.
+type recipeItemQuote struct {
+	price  int
+	vendor bool
+	err    error
+}
+
+// recipeItemPrice returns the price of buying an item teaching a recipe, and whether it's bought from a vendor rather
+// than the auction house, pricing it on first use.
+func (pl *planner) recipeItemPrice(id int) (int, bool, error) {
+	quote, ok := pl.teaching[id]
+	if !ok {
+		price, vendor, err := pl.priceRecipeItem(id)
+		quote = &recipeItemQuote{price: price, vendor: vendor, err: err}
+		pl.teaching[id] = quote
+	}
+
+	return quote.price, quote.vendor, quote.err
+}
+
+// priceRecipeItem prices buying an item teaching a recipe for recipeItemPrice. Items sold by vendors are priced at what
+// the vendor charges, falling back to NexusHub where the vendor data doesn't list the item. The price is never guessed,
+// so an item sold by vendors for an unknown price can't be bought.
+func (pl *planner) priceRecipeItem(id int) (int, bool, error) {
+	if vendorItem, err := pl.stores.VendorItems.GetByID(id); err == nil && vendorItem.Faction.Allows(pl.player.Faction) {
+		return vendorItem.Cost, true, nil
+	}
+
+	if item, err := pl.stores.Items.GetByID(id); err == nil && validator.PermittedValue(data.SOURCE_VENDOR, item.Source) {
+		price, err := pl.stores.NexusHub.GetVendorPrice(pl.server.Name, pl.input.Faction.String(), id)
+		if err != nil {
+			return 0, false, fmt.Errorf("%w of item %v: %v", ErrUnknownVendorPrice, id, err)
+		}
+		return price, true, nil
+	}
+
+	tsmItem, err := pl.tsmService.GetPrice(pl.player.AuctionHouseID, id)
+	if err != nil || tsmItem.MinBuyout <= 0 {
+		return 0, false, fmt.Errorf("item %v isn't available on the auction house", id)
+	}
+
+	return pl.auctionPrice(tsmItem), false, nil
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/data"
+)
+
+// newTestPlanner returns a planner levelling from start to finish, without any stores or pricing.
+func newTestPlanner(start, finish int, known ...int) *planner {
+	pl := &planner{
+		application: &application{logger: zap.NewNop().Sugar()},
+		input:       &plRequestPayload{},
+		player:      data.NewPlayer(data.PROFESSION_ALCHEMY, start, finish, 0),
+		fSkillup:    data.NewFilterSkillup(data.SKILLUP_GREEN),
+		objective:   goldObjective{},
+	}
+	for _, id := range known {
+		pl.player.AddRecipe(id)
+	}
+
+	return pl
+}
+
+// testCandidate returns a candidate giving a guaranteed skillup from its first skill level until it turns grey, made
+// from a reagent of its own.
+func testCandidate(id, grey, craftCost, learnCost int) candidate {
+	return candidate{
+		recipe:    data.Recipe{ID: id, Colors: []int{0, grey, grey, grey}, Reagents: [][]int{{id, 1}}},
+		quote:     &recipeQuote{cost: craftCost},
+		learnCost: learnCost,
+	}
+}
+
+// sameReagents returns a candidate made from the same reagent as the test candidate with the provided ID.
+func sameReagents(c candidate, id int) candidate {
+	c.recipe.Reagents = [][]int{{id, 1}}
+	return c
+}
+
+// recipeIDs returns the recipes used by each of the paths, in order.
+func recipeIDs(paths [][]segment) [][]int {
+	var res [][]int
+	for _, path := range paths {
+		var ids []int
+		for _, seg := range path {
+			ids = append(ids, seg.recipe.ID)
+		}
+		res = append(res, ids)
+	}
+
+	return res
+}
+
+func TestSearchPaths(t *testing.T) {
+	tests := []struct {
+		name       string
+		finish     int
+		k          int
+		known      []int
+		candidates map[int][]candidate
+		want       [][]int
+	}{
+		{
+			name:   "cheapest run",
+			finish: 3,
+			k:      1,
+			candidates: map[int][]candidate{
+				0: {testCandidate(1, 100, 10, 0), testCandidate(2, 100, 20, 0)},
+			},
+			want: [][]int{{1}},
+		},
+		{
+			name:   "learning cost spread over the run",
+			finish: 3,
+			k:      2,
+			candidates: map[int][]candidate{
+				0: {testCandidate(1, 100, 10, 100), testCandidate(2, 100, 50, 0)},
+			},
+			want: [][]int{{1}, {2}},
+		},
+		{
+			name:   "learned recipe reused without paying again",
+			finish: 3,
+			k:      2,
+			candidates: map[int][]candidate{
+				0: {testCandidate(1, 100, 10, 1000)},
+				1: {testCandidate(1, 100, 10, 1000), testCandidate(2, 2, 1, 0)},
+				2: {testCandidate(1, 100, 10, 1000)},
+			},
+			want: [][]int{{1, 2, 1}, {1}},
+		},
+		{
+			name:   "learned recipe reused along a dearer way",
+			finish: 3,
+			k:      1,
+			candidates: map[int][]candidate{
+				0: {testCandidate(1, 3, 10, 100), testCandidate(2, 1, 105, 0)},
+				1: {testCandidate(3, 2, 1, 0)},
+				2: {testCandidate(1, 3, 10, 100)},
+			},
+			want: [][]int{{1, 3, 1}},
+		},
+		{
+			name:   "tie keeps the first found",
+			finish: 2,
+			k:      2,
+			candidates: map[int][]candidate{
+				0: {testCandidate(1, 100, 10, 0), testCandidate(2, 100, 10, 0)},
+			},
+			want: [][]int{{1}, {2}},
+		},
+		{
+			name:   "tie prefers a known recipe",
+			finish: 2,
+			k:      2,
+			known:  []int{2},
+			candidates: map[int][]candidate{
+				0: {testCandidate(1, 100, 10, 0), testCandidate(2, 100, 10, 0)},
+			},
+			want: [][]int{{2}, {1}},
+		},
+		{
+			name:   "recipes made from the same reagents collapse",
+			finish: 2,
+			k:      2,
+			candidates: map[int][]candidate{
+				0: {testCandidate(1, 100, 10, 0), sameReagents(testCandidate(2, 100, 10, 0), 1), testCandidate(3, 100, 20, 0)},
+			},
+			want: [][]int{{1}, {3}},
+		},
+		{
+			name:   "adding a recipe to another sequence isn't an alternative",
+			finish: 3,
+			k:      2,
+			candidates: map[int][]candidate{
+				0: {testCandidate(1, 3, 10, 0), testCandidate(3, 3, 12, 0)},
+				1: {testCandidate(1, 3, 10, 0), testCandidate(2, 2, 11, 0)},
+				2: {testCandidate(1, 3, 10, 0)},
+			},
+			want: [][]int{{1}, {3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl := newTestPlanner(0, tt.finish, tt.known...)
+			paths, err := pl.searchPaths(tt.k, func(skill int) []candidate { return tt.candidates[skill] })
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := recipeIDs(paths); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchPathsCooldown(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxDays int
+		want    int
+	}{
+		// the cheap recipe can only be crafted twice a day, however many runs it's split across
+		{name: "capped by max days", maxDays: 1, want: 2},
+		{name: "uncapped without max days", want: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl := newTestPlanner(0, 6)
+			pl.input.MaxDays = tt.maxDays
+
+			cheap := testCandidate(1, 100, 1, 0)
+			cheap.recipe.Cooldown = SECONDS_PER_DAY
+			dear := testCandidate(2, 100, 10, 0)
+
+			paths, err := pl.searchPaths(1, func(skill int) []candidate { return []candidate{cheap, dear} })
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			crafts := 0
+			for _, seg := range paths[0] {
+				if seg.recipe.ID == cheap.recipe.ID {
+					crafts += seg.crafts
+				}
+			}
+			if crafts != tt.want {
+				t.Errorf("got %v crafts subject to the cooldown, want %v", crafts, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitForTraining(t *testing.T) {
+	recipe := testCandidate(1, 200, 10, 0).recipe
+	seg := func(start, end, crafts int) segment {
+		return segment{recipe: recipe, start: start, end: end, crafts: crafts}
+	}
+
+	tests := []struct {
+		name     string
+		finish   int
+		segments []segment
+		want     []segment
+	}{
+		{
+			name:     "split where the next rank becomes available",
+			finish:   105,
+			segments: []segment{seg(1, 105, 104)},
+			want:     []segment{seg(1, 50, 49), seg(50, 105, 55)},
+		},
+		{
+			name:     "split at each rank",
+			finish:   200,
+			segments: []segment{seg(1, 200, 199)},
+			want:     []segment{seg(1, 50, 49), seg(50, 125, 75), seg(125, 200, 75)},
+		},
+		{
+			name:     "no split without training",
+			finish:   75,
+			segments: []segment{seg(1, 75, 74)},
+			want:     []segment{seg(1, 75, 74)},
+		},
+		{
+			name:     "no split before the segment passing the cap",
+			finish:   105,
+			segments: []segment{seg(1, 52, 51), seg(52, 105, 53)},
+			want:     []segment{seg(1, 52, 51), seg(52, 105, 53)},
+		},
+		{
+			name:     "no split at a segment boundary",
+			finish:   105,
+			segments: []segment{seg(1, 50, 49), seg(50, 105, 55)},
+			want:     []segment{seg(1, 50, 49), seg(50, 105, 55)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl := newTestPlanner(1, tt.finish)
+			pl.rank = data.RANK_APPRENTICE
+			if got := pl.splitForTraining(tt.segments); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInsertLink(t *testing.T) {
+	link := func(key, cost, id int) planLink {
+		return planLink{key: key, cost: cost, recipe: data.Recipe{ID: id}}
+	}
+
+	tests := []struct {
+		name   string
+		k      int
+		known  []int
+		links  []planLink
+		insert planLink
+		want   []planLink
+	}{
+		{
+			name:   "cheaper goes first",
+			k:      2,
+			links:  []planLink{link(1, 20, 1)},
+			insert: link(2, 10, 2),
+			want:   []planLink{link(2, 10, 2), link(1, 20, 1)},
+		},
+		{
+			name:   "beyond k dropped",
+			k:      1,
+			links:  []planLink{link(1, 10, 1)},
+			insert: link(2, 20, 2),
+			want:   []planLink{link(1, 10, 1)},
+		},
+		{
+			name:   "k exceeded drops the most expensive",
+			k:      2,
+			links:  []planLink{link(1, 10, 1), link(2, 20, 2)},
+			insert: link(3, 15, 3),
+			want:   []planLink{link(1, 10, 1), link(3, 15, 3)},
+		},
+		{
+			name:   "tie goes after",
+			k:      2,
+			links:  []planLink{link(1, 10, 1)},
+			insert: link(2, 10, 2),
+			want:   []planLink{link(1, 10, 1), link(2, 10, 2)},
+		},
+		{
+			name:   "tie goes before when known",
+			k:      2,
+			known:  []int{2},
+			links:  []planLink{link(1, 10, 1)},
+			insert: link(2, 10, 2),
+			want:   []planLink{link(2, 10, 2), link(1, 10, 1)},
+		},
+		{
+			name:   "same key cheaper replaces",
+			k:      2,
+			links:  []planLink{link(1, 10, 1), link(2, 20, 2)},
+			insert: link(2, 5, 2),
+			want:   []planLink{link(2, 5, 2), link(1, 10, 1)},
+		},
+		{
+			name:   "same key dearer ignored",
+			k:      2,
+			links:  []planLink{link(1, 10, 1), link(2, 20, 2)},
+			insert: link(1, 30, 1),
+			want:   []planLink{link(1, 10, 1), link(2, 20, 2)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl := newTestPlanner(0, 1, tt.known...)
+			links := append([]planLink(nil), tt.links...)
+			pl.insertLink(&links, tt.k, tt.insert)
+			if !reflect.DeepEqual(links, tt.want) {
+				t.Errorf("got %v, want %v", links, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriceAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		required []int
+		skill    int
+		kept     bool
+	}{
+		{name: "no skill required", skill: 300, kept: true},
+		{name: "still short of it", required: []int{200}, skill: 199, kept: true},
+		{name: "reaching it", required: []int{200}, skill: 200, kept: false},
+		{name: "still past it", required: []int{50}, skill: 300, kept: true},
+		{name: "dropping below it", required: []int{50}, skill: 49, kept: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pl := newTestPlanner(100, 450)
+			pl.skill, pl.pricedAt = 100, [2]int{0, math.MaxInt}
+			pl.quotes = map[int]*recipeQuote{1: {cost: 10}}
+			for _, required := range tt.required {
+				pl.dependsOn(required)
+			}
+
+			pl.priceAt(tt.skill)
+			if _, kept := pl.quotes[1]; kept != tt.kept {
+				t.Errorf("quote kept %v, want %v", kept, tt.kept)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/data"
+)
+
+// reputationNote returns a note for a recipe the player must buy from a vendor requiring reputation they may not have,
+// as they haven't provided their standing with its faction. Recipes the player can already buy, or already knows,
+// need no note.
+func (pl *planner) reputationNote(r *data.Recipe) string {
+	if pl.player.HasRecipe(r.ID) {
+		return ""
+	}
+
+	var unknown []string
+	for reputation, required := range r.RequiredReputation(pl.player.Faction) {
+		standing, ok := pl.input.Reputation[reputation]
+		if ok && standing >= required {
+			return ""
+		}
+		if !ok {
+			unknown = append(unknown, fmt.Sprintf("%v with %v", required, reputation))
+		}
+	}
+
+	if len(unknown) == 0 {
+		return ""
+	}
+	sort.Strings(unknown)
+
+	return fmt.Sprintf("requires %v", strings.Join(unknown, " or "))
+}
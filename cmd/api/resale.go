@@ -0,0 +1,41 @@
+package main
+
+import (
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/data"
+)
+
+// Sources of resale value for crafted items
+const (
+	RESALE_MARKET_VALUE string = "marketvalue"
+	RESALE_VENDOR       string = "vendor"
+
+	// The auction house keeps 5% of the sale price
+	AUCTION_HOUSE_CUT int = 5
+)
+
+// resaleValue returns the value of selling whatever a single craft of the recipe creates, using the source requested.
+// Recipes that create nothing (e.g. enchants) or items that can't be priced have no resale value.
+func (pl *planner) resaleValue(r *data.Recipe) int {
+	id := r.CreatedItem()
+	if pl.input.Resale == "" || id == 0 {
+		return 0
+	}
+
+	switch pl.input.Resale {
+	case RESALE_MARKET_VALUE:
+		tsmItem, err := pl.tsmService.GetPrice(pl.player.AuctionHouseID, id)
+		if err != nil {
+			return 0
+		}
+		return int(float64(tsmItem.MarketValue*(100-AUCTION_HOUSE_CUT)/100) * r.Yield())
+	case RESALE_VENDOR:
+		price, err := pl.stores.NexusHub.GetSellPrice(pl.server.Name, pl.input.Faction.String(), id)
+		if err != nil {
+			pl.logger.Debugf("unable to get vendor sell price of %v: %v", id, err)
+			return 0
+		}
+		return int(float64(price) * r.Yield())
+	default:
+		return 0
+	}
+}
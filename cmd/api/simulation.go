@@ -0,0 +1,90 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/data"
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/validator"
+)
+
+const (
+	MAXIMUM_SIMULATIONS int = 10_000
+)
+
+// simulate follows the provided plan a number of times, rolling for each skillup using the actual chance of success
+// for the recipe's colour at that skill level, and summarises the spread of the total cost. Each craft is costed at
+// the average cost of a craft in its step as planned, and training costs are fixed.
+func (app *application) simulate(plan *data.Plan, runs int) (*data.SimulationResult, error) {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	recipes := make([]*data.Recipe, len(plan.Steps))
+	for i, step := range plan.Steps {
+		if step.Rank != data.RANK_UNDEFINED {
+			continue
+		}
+
+		recipe, err := app.stores.Recipes.GetByID(step.RecipeID)
+		if err != nil {
+			return nil, err
+		}
+		recipes[i] = recipe
+	}
+
+	totals := make([]int, runs)
+	var sumCost, sumCrafts float64
+
+	for i := range totals {
+		var total float64
+		crafts := 0
+		for j, step := range plan.Steps {
+			if step.Rank != data.RANK_UNDEFINED {
+				total += float64(step.Cost)
+				continue
+			}
+
+			craftCost := float64(step.Cost-step.LearnCost) / float64(step.Crafts)
+			total += float64(step.LearnCost)
+			for level := step.SkillStart; level < step.SkillEnd; level++ {
+				n := sampleCrafts(rng, recipes[j].SkillupChance(level+plan.SkillBonus))
+				crafts += n
+				total += float64(n) * craftCost
+			}
+		}
+
+		totals[i] = int(math.Round(total))
+		sumCost += total
+		sumCrafts += float64(crafts)
+	}
+
+	sort.Ints(totals)
+
+	return &data.SimulationResult{
+		Runs:           runs,
+		ExpectedCrafts: sumCrafts / float64(runs),
+		ExpectedCost:   int(math.Round(sumCost / float64(runs))),
+		P50:            percentile(totals, 0.50),
+		P90:            percentile(totals, 0.90),
+		P99:            percentile(totals, 0.99),
+	}, nil
+}
+
+// sampleCrafts returns the number of crafts it took to gain a single skillup, where each craft succeeds with the
+// provided chance. This is a draw from a geometric distribution, so it's sampled directly rather than crafting one at
+// a time.
+func sampleCrafts(rng *rand.Rand, chance float64) int {
+	if chance >= 1 {
+		return 1
+	}
+
+	// 1 - Float64() is in (0, 1], avoiding log(0)
+	return int(math.Ceil(math.Log(1-rng.Float64()) / math.Log(1-chance)))
+}
+
+// percentile returns the nearest-rank percentile of an already sorted slice.
+func percentile(sorted []int, p float64) int {
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[validator.Max(idx, 0)]
+}
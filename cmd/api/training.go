@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/data"
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/validator"
+)
+
+// currentRank returns the rank of the profession the player has already trained. When not provided it's assumed to be
+// the lowest rank able to reach their current skill.
+func (pl *planner) currentRank() data.Rank {
+	if pl.input.CurrentRank != data.RANK_UNDEFINED {
+		return pl.input.CurrentRank
+	}
+
+	return data.RankForSkill(pl.input.StartLevel)
+}
+
+// train adds a step to the plan for each rank that must be trained before crafting from one skill to another. Ranks
+// are trained as soon as the players skill allows, which may be part way through crafting.
+func (pl *planner) train(plan *data.Plan, start, end int) {
+	for end > pl.rank.Cap() && pl.rank < data.RANK_GRAND_MASTER {
+		pl.rank++
+
+		skill := validator.Max(start, pl.rank.RequiredSkill())
+		cost := pl.rank.TrainingCost()
+		plan.AddStep(data.PlanStep{
+			SkillStart: skill,
+			SkillEnd:   skill,
+			RecipeName: fmt.Sprintf("%v training", pl.rank),
+			LearnCost:  cost,
+			Cost:       cost,
+			Purchases:  data.NewPurchaseOrder(),
+			Rank:       pl.rank,
+		})
+	}
+}
+
+// splitForTraining splits each segment taking the player beyond the cap of their rank at the skill level the next rank
+// becomes available, so that the rank is trained after the crafts reaching its required skill rather than before the
+// segment they're part of.
+func (pl *planner) splitForTraining(segments []segment) []segment {
+	var res []segment
+
+	rank := pl.rank
+	for _, seg := range segments {
+		for seg.end > rank.Cap() && rank < data.RANK_GRAND_MASTER {
+			rank++
+
+			at := rank.RequiredSkill()
+			if at <= seg.start || at >= seg.end {
+				continue
+			}
+
+			before := seg
+			before.end, before.crafts = at, pl.craftsBetween(&seg.recipe, seg.start, at)
+			seg.start, seg.crafts = at, seg.crafts-before.crafts
+			res = append(res, before)
+		}
+		res = append(res, seg)
+	}
+
+	return res
+}
+
+// craftsBetween returns the number of times a recipe must be crafted to go from one skill level to another.
+func (pl *planner) craftsBetween(r *data.Recipe, start, end int) int {
+	bonus := pl.player.SkillBonus(pl.player.Profession)
+
+	crafts := 0
+	for level := start; level < end; level++ {
+		crafts += pl.getRequiredCrafts(level+bonus, r)
+	}
+
+	return crafts
+}
+
+// requiredLevel returns the character level required to learn a recipe, being the lowest level required to use any of
+// the items teaching it that the player can buy. Levels are taken from NexusHub, and items whose level isn't known are
+// assumed to require none. Recipes learned from a trainer only require the rank they're learned at.
+func (pl *planner) requiredLevel(r *data.Recipe) int {
+	if r.TrainingCost > 0 || validator.PermittedValue(data.SOURCE_TRAINER, r.Source) {
+		return 0
+	}
+
+	level := math.MaxInt
+	for _, id := range pl.stores.Items.GetRecipeItemIDs(r.ID) {
+		if item, err := pl.stores.Items.GetByID(id); err == nil && !item.Faction.Allows(pl.player.Faction) {
+			continue
+		}
+
+		if _, ok := pl.levels[id]; !ok {
+			required, err := pl.stores.NexusHub.GetRequiredLevel(pl.server.Name, pl.input.Faction.String(), id)
+			if err != nil {
+				pl.logger.Debugf("unable to get the level required to use %v: %v", id, err)
+			}
+			pl.levels[id] = required
+		}
+		level = validator.Min(level, pl.levels[id])
+	}
+
+	if level == math.MaxInt {
+		return 0
+	}
+
+	return level
+}
+
+// blockedError is returned when no recipe can be found at a skill level until the player reaches a higher character
+// level.
+type blockedError struct {
+	Skill          int `json:"skill"`
+	CharacterLevel int `json:"character_level"`
+}
+
+func (e *blockedError) Error() string {
+	return fmt.Sprintf("blocked at %v until character level %v", e.Skill, e.CharacterLevel)
+}
+
+// blockedAt returns the character level the player must reach before a craft can be found at the provided skill, or
+// zero if it isn't their character level holding them back.
+func (pl *planner) blockedAt(skill int) int {
+	if pl.input.CharacterLevel == 0 {
+		return 0
+	}
+
+	level := math.MaxInt
+	unfiltered := data.NewFilterLevel(0, nil, pl.requiredLevel)
+	candidates := pl.stores.Recipes.GetFiltered(skill, pl.player.Profession, pl.player.Faction, pl.fSource, pl.fSkillup, unfiltered, pl.fRep)
+	for _, recipe := range candidates {
+		level = validator.Min(level, pl.requiredLevel(&recipe))
+	}
+
+	if level == math.MaxInt || level <= pl.input.CharacterLevel {
+		return 0
+	}
+
+	return level
+}
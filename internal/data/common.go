@@ -3,6 +3,8 @@ package data
 const (
 	MINIMUM_PROFESSION_LEVEL int = 1
 	MAXIMUM_PROFESSION_LEVEL int = 450
+	MINIMUM_CHARACTER_LEVEL  int = 1
+	MAXIMUM_CHARACTER_LEVEL  int = 80
 
 	UNDEFINED_TYPE = "undefined"
 )
@@ -35,8 +37,78 @@ func (f Faction) String() string {
 	}
 }
 
-// Represents all learnable primary professions (+ cooking). Uses Wowhead numbers as identifiers. Sort of an enum type,
-// but sort of not.
+// Allows returns whether something restricted to the faction is available to a player of the provided faction. Things
+// without a faction are available to both.
+func (f Faction) Allows(faction Faction) bool {
+	return f == FACTION_UNDEFINED || f == faction
+}
+
+// Enum representing playable races, using the same numbers as the game. Some races have a bonus to a profession.
+type Race int
+
+const (
+	RACE_UNDEFINED Race = iota
+	RACE_HUMAN
+	RACE_ORC
+	RACE_DWARF
+	RACE_NIGHT_ELF
+	RACE_UNDEAD
+	RACE_TAUREN
+	RACE_GNOME
+	RACE_TROLL
+	_
+	RACE_BLOOD_ELF
+	RACE_DRAENEI
+)
+
+func (r Race) String() string {
+	switch r {
+	case RACE_HUMAN:
+		return "human"
+	case RACE_ORC:
+		return "orc"
+	case RACE_DWARF:
+		return "dwarf"
+	case RACE_NIGHT_ELF:
+		return "night elf"
+	case RACE_UNDEAD:
+		return "undead"
+	case RACE_TAUREN:
+		return "tauren"
+	case RACE_GNOME:
+		return "gnome"
+	case RACE_TROLL:
+		return "troll"
+	case RACE_BLOOD_ELF:
+		return "blood elf"
+	case RACE_DRAENEI:
+		return "draenei"
+	default:
+		return UNDEFINED_TYPE
+	}
+}
+
+// ProfessionBonus returns the racial bonus to the skill of a profession.
+func (r Race) ProfessionBonus(p Profession) int {
+	switch {
+	case r == RACE_GNOME && p == PROFESSION_ENGINEERING:
+		return 15
+	case r == RACE_DRAENEI && p == PROFESSION_JEWELCRAFTING:
+		return 5
+	case r == RACE_BLOOD_ELF && p == PROFESSION_ENCHANTING:
+		return 10
+	case r == RACE_TAUREN && p == PROFESSION_HERBALISM:
+		return 15
+	default:
+		return 0
+	}
+}
+
+// Represents all learnable primary professions (+ cooking, first aid and fishing). Uses Wowhead numbers as identifiers.
+// Sort of an enum type, but sort of not. Mining is levelled through its smelting recipes, which leave ranges no smelt
+// gives skillups for (see SMELTING_GAPS) where ore must be mined instead, so only ranges smelting covers can be planned.
+// Fishing and herbalism have no recipes of their own, and the fish and herbs they
+// supply are priced like any other reagent unless the player supplies them themselves.
 type Profession int
 
 const (
@@ -45,12 +117,39 @@ const (
 	PROFESSION_COOKING        Profession = 185
 	PROFESSION_ENCHANTING     Profession = 333
 	PROFESSION_ENGINEERING    Profession = 202
+	PROFESSION_FIRST_AID      Profession = 129
+	PROFESSION_FISHING        Profession = 356
+	PROFESSION_HERBALISM      Profession = 182
 	PROFESSION_INSCRIPTION    Profession = 773
 	PROFESSION_JEWELCRAFTING  Profession = 755
 	PROFESSION_LEATHERWORKING Profession = 165
+	PROFESSION_MINING         Profession = 186
 	PROFESSION_TAILORING      Profession = 197
 )
 
+// SMELTING_GAPS are the ranges of mining skill, from and up to, that no smelt gives skillups for.
+var SMELTING_GAPS = [][2]int{{290, 300}, {315, 325}, {340, 345}, {390, 400}, {425, 450}}
+
+// Plannable returns whether a profession is levelled by crafting, and so can be planned.
+func (p Profession) Plannable() bool {
+	return p.String() != UNDEFINED_TYPE && p != PROFESSION_FISHING && p != PROFESSION_HERBALISM
+}
+
+// UncoveredRange returns the first range of skill levels between start and finish that the professions recipes give no
+// skillups for, and so can't be planned, if there is one.
+func (p Profession) UncoveredRange(start, finish int) ([2]int, bool) {
+	if p != PROFESSION_MINING {
+		return [2]int{}, false
+	}
+
+	for _, gap := range SMELTING_GAPS {
+		if start < gap[1] && finish > gap[0] {
+			return gap, true
+		}
+	}
+	return [2]int{}, false
+}
+
 func (p Profession) String() string {
 	switch p {
 	case PROFESSION_ALCHEMY:
@@ -63,12 +162,20 @@ func (p Profession) String() string {
 		return "enchanting"
 	case PROFESSION_ENGINEERING:
 		return "engineering"
+	case PROFESSION_FIRST_AID:
+		return "first aid"
+	case PROFESSION_FISHING:
+		return "fishing"
+	case PROFESSION_HERBALISM:
+		return "herbalism"
 	case PROFESSION_INSCRIPTION:
 		return "inscription"
 	case PROFESSION_JEWELCRAFTING:
 		return "jewelcrafting"
 	case PROFESSION_LEATHERWORKING:
 		return "leatherworking"
+	case PROFESSION_MINING:
+		return "mining"
 	case PROFESSION_TAILORING:
 		return "tailoring"
 	default:
@@ -76,6 +183,123 @@ func (p Profession) String() string {
 	}
 }
 
+// Represents the rank of a profession trained by the player, each of which raises the maximum skill the player can
+// reach by 75. Each rank costs gold to train, and requires a minimum character level and the skill to be approaching
+// the cap of the previous rank.
+type Rank int
+
+const (
+	RANK_UNDEFINED Rank = iota
+	RANK_APPRENTICE
+	RANK_JOURNEYMAN
+	RANK_EXPERT
+	RANK_ARTISAN
+	RANK_MASTER
+	RANK_GRAND_MASTER
+)
+
+func (r Rank) String() string {
+	switch r {
+	case RANK_APPRENTICE:
+		return "apprentice"
+	case RANK_JOURNEYMAN:
+		return "journeyman"
+	case RANK_EXPERT:
+		return "expert"
+	case RANK_ARTISAN:
+		return "artisan"
+	case RANK_MASTER:
+		return "master"
+	case RANK_GRAND_MASTER:
+		return "grand master"
+	default:
+		return UNDEFINED_TYPE
+	}
+}
+
+// Cap returns the maximum skill that can be reached with the rank.
+func (r Rank) Cap() int {
+	return int(r) * 75
+}
+
+// TrainingCost returns the cost of training the rank.
+func (r Rank) TrainingCost() int {
+	switch r {
+	case RANK_APPRENTICE:
+		return 10
+	case RANK_JOURNEYMAN:
+		return 500
+	case RANK_EXPERT:
+		return 10_000
+	case RANK_ARTISAN:
+		return 50_000
+	case RANK_MASTER:
+		return 100_000
+	case RANK_GRAND_MASTER:
+		return 350_000
+	default:
+		return 0
+	}
+}
+
+// CharacterLevel returns the character level required to train the rank.
+func (r Rank) CharacterLevel() int {
+	switch r {
+	case RANK_APPRENTICE:
+		return 5
+	case RANK_JOURNEYMAN:
+		return 10
+	case RANK_EXPERT:
+		return 20
+	case RANK_ARTISAN:
+		return 35
+	case RANK_MASTER:
+		return 50
+	case RANK_GRAND_MASTER:
+		return 65
+	default:
+		return 0
+	}
+}
+
+// RequiredSkill returns the skill required to train the rank.
+func (r Rank) RequiredSkill() int {
+	switch r {
+	case RANK_APPRENTICE:
+		return 0
+	case RANK_JOURNEYMAN:
+		return 50
+	case RANK_EXPERT:
+		return 125
+	case RANK_ARTISAN:
+		return 200
+	case RANK_MASTER:
+		return 275
+	case RANK_GRAND_MASTER:
+		return 350
+	default:
+		return 0
+	}
+}
+
+// RankForSkill returns the lowest rank at which the provided skill can be reached.
+func RankForSkill(skill int) Rank {
+	rank := RANK_APPRENTICE
+	for rank.Cap() < skill && rank < RANK_GRAND_MASTER {
+		rank++
+	}
+	return rank
+}
+
+// HighestRank returns the highest rank that can be trained at the provided character level.
+func HighestRank(characterLevel int) Rank {
+	rank := RANK_UNDEFINED
+	for rank < RANK_GRAND_MASTER && (rank+1).CharacterLevel() <= characterLevel {
+		rank++
+	}
+	return rank
+}
+
 // Represents the lowest allowable difficulty when crafting. That is to say, if you're only willing to craft orange
 // level recipes for guaranteed skillups, you can enforce that. If you want to allow green recipes and are somewhat
 // willing to accept the indeterminate nature of crafting vs skillups, you can do that too.
@@ -177,10 +401,27 @@ func (s Source) String() string {
 	}
 }
 
+// PurchaseOrder details everything that must be acquired to perform some number of crafts, split by where it is
+// acquired from. Cost is the gold actually spent, i.e. vendor and auction house purchases. Crafted and converted items
+// (e.g. pigments milled from herbs) are listed for information only as whatever was used to make them is already
+// included in the other lists, and items taken from the inventory (e.g. crafted by an earlier step) cost nothing other
+// than any opportunity cost of using the players own items rather than selling them. Items the player farms themselves
+// cost nothing but time, which is valued at whatever the player could otherwise earn in that time.
 type PurchaseOrder struct {
-	Cost  int `json:"cost"`
-	Items []struct {
-		ID       int `json:"id"`
-		Quantity int `json:"quantity"`
-	} `json:"items"`
+	Cost            int            `json:"cost"`
+	OpportunityCost int            `json:"opportunity_cost"`
+	FarmCost        int            `json:"farm_cost"`
+	Vendor          []PurchaseItem `json:"vendor"`
+	AuctionHouse    []PurchaseItem `json:"auction_house"`
+	Crafted         []PurchaseItem `json:"crafted"`
+	Inventory       []PurchaseItem `json:"inventory"`
+	Farmed          []PurchaseItem `json:"farmed"`
+	Converted       []PurchaseItem `json:"converted"`
+}
+
+// PurchaseItem is a single line of a PurchaseOrder.
+type PurchaseItem struct {
+	ID       int `json:"id"`
+	Quantity int `json:"quantity"`
+	Cost     int `json:"cost"`
 }
@@ -0,0 +1,139 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+
+	"go.uber.org/zap"
+)
+
+// Represents the ways in which one item can be broken down into others by a profession, e.g. milling herbs into
+// pigments with inscription.
+type ConversionMethod int
+
+const (
+	CONVERT_UNDEFINED ConversionMethod = iota
+	CONVERT_MILLING
+	CONVERT_PROSPECTING
+	CONVERT_DISENCHANTING
+)
+
+func (m ConversionMethod) String() string {
+	switch m {
+	case CONVERT_MILLING:
+		return "milling"
+	case CONVERT_PROSPECTING:
+		return "prospecting"
+	case CONVERT_DISENCHANTING:
+		return "disenchanting"
+	default:
+		return UNDEFINED_TYPE
+	}
+}
+
+// Profession returns the profession required to use a conversion method.
+func (m ConversionMethod) Profession() Profession {
+	switch m {
+	case CONVERT_MILLING:
+		return PROFESSION_INSCRIPTION
+	case CONVERT_PROSPECTING:
+		return PROFESSION_JEWELCRAFTING
+	case CONVERT_DISENCHANTING:
+		return PROFESSION_ENCHANTING
+	default:
+		return 0
+	}
+}
+
+// Conversion is the breaking down of an item into others, e.g. prospecting 5 Cobalt Ore. Results are random so each
+// yield is the expected quantity of an item from a single conversion.
+type Conversion struct {
+	ID       int               `json:"id"` // itemID of the item converted
+	Name     string            `json:"name"`
+	Method   ConversionMethod  `json:"method"`
+	Quantity int               `json:"quantity"` // number of items consumed by a single conversion
+	Skill    int               `json:"skill"`    // skill required in the profession of the conversion method
+	Yields   []ConversionYield `json:"yields"`
+}
+
+type ConversionYield struct {
+	ID   int     `json:"id"`
+	Rate float64 `json:"rate"`
+}
+
+// Yield returns the expected quantity of an item from a single conversion.
+func (c *Conversion) Yield(id int) float64 {
+	for _, v := range c.Yields {
+		if v.ID == id {
+			return v.Rate
+		}
+	}
+	return 0
+}
+
+// Produced returns the quantity of an item expected from a number of conversions, rounded down (allowing for floating
+// point error) as only whole items can be produced.
+func (c *Conversion) Produced(id, conversions int) int {
+	return int(math.Floor(float64(conversions)*c.Yield(id) + 1e-9))
+}
+
+// Holds the data retrieved from static JSON files for Conversions
+type ConversionStore struct {
+	dataslice []Conversion
+	datamap   map[int]Conversion
+	results   map[int][]int // itemID -> IDs of the items that can be converted into it
+	logger    *zap.SugaredLogger
+}
+
+// Instantiates the store, loading and parsing the JSON files to make available via the stores methods.
+func NewConversionStore(logger *zap.SugaredLogger) *ConversionStore {
+	var data []Conversion
+
+	bytes, err := os.ReadFile("data/conversions.json")
+	if err != nil {
+		panic(err)
+	}
+
+	err = json.Unmarshal(bytes, &data)
+	if err != nil {
+		panic(err)
+	}
+
+	datamap := make(map[int]Conversion, len(data))
+	results := make(map[int][]int)
+	for _, c := range data {
+		datamap[c.ID] = c
+		for _, v := range c.Yields {
+			results[v.ID] = append(results[v.ID], c.ID)
+		}
+	}
+
+	return &ConversionStore{
+		dataslice: data,
+		datamap:   datamap,
+		results:   results,
+		logger:    logger,
+	}
+}
+
+// GetByID returns the conversion of an item from its provided ID
+func (c *ConversionStore) GetByID(id int) (*Conversion, error) {
+	if val, ok := c.datamap[id]; ok {
+		return &val, nil
+	}
+	return nil, fmt.Errorf("couldn't locate conversion of item with id %v", id)
+}
+
+// GetByResult returns all conversions that can produce the provided item.
+func (c *ConversionStore) GetByResult(id int) []*Conversion {
+	var res []*Conversion
+
+	for _, v := range c.results[id] {
+		conversion := c.datamap[v]
+		res = append(res, &conversion)
+	}
+
+	return res
+}
@@ -41,20 +41,29 @@ func (f *FilterSkillup) Filter(r *Recipe, current int) bool {
 	}
 }
 
-// FilterSource - Filters out potential recipes based upon the source from which they are obtained.
+// FilterSource - Filters out potential recipes based upon the source from which they are obtained. Recipes the caller
+// already knows are always suitable, whatever their source.
 type FilterSource struct {
-	Allowable []Source `json:"-"`
+	Allowable []Source     `json:"-"`
+	Known     map[int]bool `json:"-"`
 }
 
-// NewFilterSource returns a struct used to filter out recipes based upon the callers provided suitable sources.
-func NewFilterSource(input []Source) *FilterSource {
+// NewFilterSource returns a struct used to filter out recipes based upon the callers provided suitable sources and the
+// recipes they already know.
+func NewFilterSource(input []Source, known map[int]bool) *FilterSource {
 	return &FilterSource{
 		Allowable: input,
+		Known:     known,
 	}
 }
 
 // Filter returns a bool indicating whether a recipe is from a suitable source.
 func (f *FilterSource) Filter(r *Recipe) bool {
+	// Recipes that are already known don't need to be obtained
+	if f.Known[r.ID] {
+		return true
+	}
+
 	// If we have a value for LearnedAt, it should be either automatically given or from a trainer so just accept it
 	if r.LearnedAt != 0 {
 		return true
@@ -69,3 +78,64 @@ func (f *FilterSource) Filter(r *Recipe) bool {
 
 	return false
 }
+
+// FilterLevel - Filters out potential recipes based upon the character level required to learn them. Recipes the
+// caller already knows are always suitable, as is every recipe when the callers level isn't known.
+type FilterLevel struct {
+	Level         int                 `json:"-"`
+	Known         map[int]bool        `json:"-"`
+	RequiredLevel func(r *Recipe) int `json:"-"` // character level required to learn a recipe, or zero if there's none
+}
+
+// NewFilterLevel returns a struct used to filter out recipes based upon the callers character level, the recipes they
+// already know and the character level required to learn each recipe.
+func NewFilterLevel(level int, known map[int]bool, requiredLevel func(r *Recipe) int) *FilterLevel {
+	return &FilterLevel{
+		Level:         level,
+		Known:         known,
+		RequiredLevel: requiredLevel,
+	}
+}
+
+// Filter returns a bool indicating whether a recipe can be learned at the callers character level.
+func (f *FilterLevel) Filter(r *Recipe) bool {
+	if f.Level == 0 || f.Known[r.ID] {
+		return true
+	}
+
+	return f.RequiredLevel(r) <= f.Level
+}
+
+// FilterReputation - Filters out potential recipes sold by vendors requiring a better standing than the caller has
+// earned with their faction. Recipes the caller already knows are always suitable, as are those sold by a faction the
+// caller hasn't provided their standing with, since they may well have earned it.
+type FilterReputation struct {
+	Faction   Faction                 `json:"-"`
+	Standings map[Reputation]Standing `json:"-"`
+	Known     map[int]bool            `json:"-"`
+}
+
+// NewFilterReputation returns a struct used to filter out recipes based upon the callers faction, their standing with
+// the factions selling recipes, and the recipes they already know.
+func NewFilterReputation(faction Faction, standings map[Reputation]Standing, known map[int]bool) *FilterReputation {
+	return &FilterReputation{
+		Faction:   faction,
+		Standings: standings,
+		Known:     known,
+	}
+}
+
+// Filter returns a bool indicating whether a recipe can be bought with the callers standing.
+func (f *FilterReputation) Filter(r *Recipe) bool {
+	if f.Known[r.ID] || len(r.Reputation) == 0 {
+		return true
+	}
+
+	for reputation, required := range r.RequiredReputation(f.Faction) {
+		if standing, ok := f.Standings[reputation]; !ok || standing >= required {
+			return true
+		}
+	}
+
+	return false
+}
@@ -21,13 +21,15 @@ type Item struct {
 	CraftedBy    []int    `json:"craftedby,omitempty"`    // [professionID, recipeSpellID]
 	TeachesCraft []int    `json:"teachescraft,omitempty"` // [professionID, recipeSpellID]
 	Source       []Source `json:"source"`
+	Faction      Faction  `json:"faction,omitempty"` // only usable by players of the faction
 }
 
 // Holds the data retrieved from static JSON files for Items
 type ItemStore struct {
-	dataslice []Item
-	datamap   map[int]Item
-	logger    *zap.SugaredLogger
+	dataslice   []Item
+	datamap     map[int]Item
+	recipeItems map[int][]int // recipeSpellID -> IDs of the items that teach it
+	logger      *zap.SugaredLogger
 }
 
 // Instantiates the store, loading and parsing the JSON files to make available via the stores methods.
@@ -45,17 +47,30 @@ func NewItemStore(logger *zap.SugaredLogger) *ItemStore {
 	}
 
 	datamap := make(map[int]Item, len(data))
+	recipeItems := make(map[int][]int)
 	for _, i := range data {
 		datamap[i.ID] = i
+		if len(i.TeachesCraft) == 2 && i.TeachesCraft[1] != 0 {
+			recipeItems[i.TeachesCraft[1]] = append(recipeItems[i.TeachesCraft[1]], i.ID)
+		}
 	}
 
 	return &ItemStore{
-		dataslice: data,
-		datamap:   datamap,
-		logger:    logger,
+		dataslice:   data,
+		datamap:     datamap,
+		recipeItems: recipeItems,
+		logger:      logger,
 	}
 }
 
+// GetByID returns an item from its provided ID
+func (i *ItemStore) GetByID(id int) (*Item, error) {
+	if val, ok := i.datamap[id]; ok {
+		return &val, nil
+	}
+	return nil, ErrorItemNotExist
+}
+
 func (i *ItemStore) GetCraftingRecipeID(itemID int) (int, error) {
 	item, ok := i.datamap[itemID]
 	if !ok {
@@ -68,3 +83,8 @@ func (i *ItemStore) GetCraftingRecipeID(itemID int) (int, error) {
 
 	return item.CraftedBy[1], nil
 }
+
+// GetRecipeItemIDs returns the IDs of all items that teach the provided recipe, e.g. a pattern or a set of plans.
+func (i *ItemStore) GetRecipeItemIDs(recipeID int) []int {
+	return i.recipeItems[recipeID]
+}
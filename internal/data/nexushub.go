@@ -75,7 +75,7 @@ func (nh *NexusHubStore) getItem(server, faction string, id int) (*NexusHubItemR
 	if bytes, err := nh.cache.Get(key); err == nil {
 		jsonErr := json.Unmarshal(bytes, data)
 		if jsonErr != nil {
-			return nil, err
+			return nil, jsonErr
 		}
 		return data, nil
 	}
@@ -83,10 +83,13 @@ func (nh *NexusHubStore) getItem(server, faction string, id int) (*NexusHubItemR
 	// Not in the cache, query NexusHub
 	reqUrl := fmt.Sprintf("%v%v-%v/%v", NEXUS_HUB_ITEM_PRICE_BASE_URL, server, faction, id)
 	res, err := nh.client.Get(reqUrl)
-	if res.StatusCode != http.StatusOK || err != nil {
+	if err != nil {
 		nh.logger.Error(err.Error())
 		return nil, err
 	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status fetching item %v: %v", id, res.StatusCode)
+	}
 
 	// Decode the response from NexusHub
 	dec := json.NewDecoder(res.Body)
@@ -130,3 +133,36 @@ func (nh *NexusHubStore) GetPriceBatch(server, faction string, id []int) (int, e
 	}
 	return total, nil
 }
+
+// GetSellPrice returns the price a vendor will pay for a provided item
+func (nh *NexusHubStore) GetSellPrice(server, faction string, id int) (int, error) {
+	data, err := nh.getItem(server, faction, id)
+	if err != nil {
+		return 0, err
+	}
+
+	return data.SellPrice, nil
+}
+
+// GetVendorPrice returns the price a vendor charges for a provided item
+func (nh *NexusHubStore) GetVendorPrice(server, faction string, id int) (int, error) {
+	data, err := nh.getItem(server, faction, id)
+	switch {
+	case err != nil:
+		return 0, err
+	case data.VendorPrice == 0:
+		return 0, errors.New("vendor price unavailable")
+	default:
+		return data.VendorPrice, nil
+	}
+}
+
+// GetRequiredLevel returns the character level required to use a provided item
+func (nh *NexusHubStore) GetRequiredLevel(server, faction string, id int) (int, error) {
+	data, err := nh.getItem(server, faction, id)
+	if err != nil {
+		return 0, err
+	}
+
+	return data.RequiredLevel, nil
+}
@@ -0,0 +1,187 @@
+package data
+
+// Plan is the result of a profession levelling request. It contains the ordered steps to follow, what each of them
+// costs, and a consolidated shopping list covering every step.
+type Plan struct {
+	Profession   Profession     `json:"profession"`
+	SkillStart   int            `json:"skill_start"`
+	SkillFinish  int            `json:"skill_finish"`
+	SkillBonus   int            `json:"skill_bonus"` // racial bonus added to the skill when determining recipe colours
+	Steps        []PlanStep     `json:"steps"`
+	TotalCost    int            `json:"total_cost"`
+	TotalResale  int            `json:"total_resale"`
+	FarmingHours float64        `json:"farming_hours"` // estimated time spent farming self-supplied items
+	CalendarDays float64        `json:"calendar_days"` // days spent waiting on cooldowns between crafts
+	CastingHours float64        `json:"casting_hours"` // estimated time spent casting the crafts of each step
+	ShoppingList *PurchaseOrder `json:"shopping_list"`
+	Shortfalls   []PurchaseItem `json:"shortfalls,omitempty"` // auction house items that can't be bought in full
+	OwnedUsed    []PurchaseItem `json:"owned_used,omitempty"` // the players own items used by the plan
+	Leftovers    []PurchaseItem `json:"leftovers,omitempty"`  // items left in the inventory once the plan is complete
+	Cooldowns    []Cooldown     `json:"cooldowns,omitempty"`  // crafts limited by a cooldown
+
+	Simulation   *SimulationResult `json:"simulation,omitempty"`
+	Alternatives []*Plan           `json:"alternatives,omitempty"` // the next cheapest plans using different recipes
+}
+
+// PlanStep is a single recipe crafted over a contiguous skill range, e.g. 'Bolt of Linen Cloth' from 1 -> 10. CraftCost
+// is the cost of a single craft when buying everything required, whereas Cost is what the step actually costs after
+// using anything already in the inventory, including the one-off cost of learning the recipe and the value of any time
+// spent farming, and less the value of reselling anything crafted if requested. The recipe is learned the first time
+// it's used, so LearnCost is only charged once. Steps training the next rank of the profession craft nothing, and cost
+// only the training.
+type PlanStep struct {
+	SkillStart   int            `json:"skill_start"`
+	SkillEnd     int            `json:"skill_end"`
+	RecipeID     int            `json:"recipe_id"`
+	RecipeName   string         `json:"recipe_name"`
+	RecipeItemID int            `json:"recipe_item_id,omitempty"` // item bought to learn the recipe, if any, also in the purchases
+	Crafts       int            `json:"crafts"`
+	CraftCost    int            `json:"craft_cost"`
+	LearnCost    int            `json:"learn_cost"`
+	ResaleValue  int            `json:"resale_value"`
+	Cost         int            `json:"cost"`
+	Purchases    *PurchaseOrder `json:"purchases"`
+	FarmRequired []int          `json:"farm_required,omitempty"` // IDs of items that can't be bought in full
+	Rank         Rank           `json:"rank,omitempty"`          // rank trained, for steps that train rather than craft
+	Note         string         `json:"note,omitempty"`          // anything else the player must do first, e.g. earn reputation
+	RunnerUps    []RunnerUp     `json:"runner_ups,omitempty"`    // the next cheapest recipes for the same skill range
+}
+
+// RunnerUp is a recipe that could have been crafted instead of a steps recipe over the same range of skill levels, and
+// how much more it would have cost as judged by the plans objective, including the cost of learning it.
+type RunnerUp struct {
+	RecipeID       int    `json:"recipe_id"`
+	RecipeName     string `json:"recipe_name"`
+	CostDifference int    `json:"cost_difference"`
+}
+
+// Cooldown is the number of crafts a plan makes subject to a single cooldown, whether to gain skill or to craft
+// reagents, and the days it takes to wait out the cooldown between each of them.
+type Cooldown struct {
+	Name   string  `json:"name"`
+	Crafts int     `json:"crafts"`
+	Days   float64 `json:"days"`
+}
+
+// SimulationResult summarises the total cost of following a plan over many simulated attempts, using the actual
+// chance of each craft granting a skillup rather than a fixed number of crafts. Percentiles are budgets that were
+// sufficient for that proportion of the attempts.
+type SimulationResult struct {
+	Runs           int     `json:"runs"`
+	ExpectedCrafts float64 `json:"expected_crafts"`
+	ExpectedCost   int     `json:"expected_cost"`
+	P50            int     `json:"p50"`
+	P90            int     `json:"p90"`
+	P99            int     `json:"p99"`
+}
+
+// NewPlan returns an empty plan for the provided profession and skill range.
+func NewPlan(profession Profession, skillStart, skillFinish int) *Plan {
+	return &Plan{
+		Profession:   profession,
+		SkillStart:   skillStart,
+		SkillFinish:  skillFinish,
+		Steps:        []PlanStep{},
+		ShoppingList: NewPurchaseOrder(),
+	}
+}
+
+// AddStep appends a step to the plan, updating the total cost and shopping list.
+func (p *Plan) AddStep(step PlanStep) {
+	p.Steps = append(p.Steps, step)
+	p.TotalCost += step.Cost
+	p.TotalResale += step.ResaleValue
+	p.ShoppingList.Merge(step.Purchases)
+}
+
+// NewPurchaseOrder returns an empty purchase order.
+func NewPurchaseOrder() *PurchaseOrder {
+	return &PurchaseOrder{
+		Vendor:       []PurchaseItem{},
+		AuctionHouse: []PurchaseItem{},
+		Crafted:      []PurchaseItem{},
+		Inventory:    []PurchaseItem{},
+		Farmed:       []PurchaseItem{},
+		Converted:    []PurchaseItem{},
+	}
+}
+
+// AddVendor records the purchase of an item from a vendor.
+func (po *PurchaseOrder) AddVendor(id, qty, cost int) {
+	po.Vendor = addPurchaseItem(po.Vendor, PurchaseItem{ID: id, Quantity: qty, Cost: cost})
+	po.Cost += cost
+}
+
+// AddAuction records the purchase of an item from the auction house.
+func (po *PurchaseOrder) AddAuction(id, qty, cost int) {
+	po.AuctionHouse = addPurchaseItem(po.AuctionHouse, PurchaseItem{ID: id, Quantity: qty, Cost: cost})
+	po.Cost += cost
+}
+
+// AddCrafted records that an item is crafted rather than bought. The cost is informational only as the reagents used
+// are expected to be recorded separately.
+func (po *PurchaseOrder) AddCrafted(id, qty, cost int) {
+	po.Crafted = addPurchaseItem(po.Crafted, PurchaseItem{ID: id, Quantity: qty, Cost: cost})
+}
+
+// AddConverted records that an item is converted from another rather than bought. As with crafted items the cost is
+// informational only.
+func (po *PurchaseOrder) AddConverted(id, qty, cost int) {
+	po.Converted = addPurchaseItem(po.Converted, PurchaseItem{ID: id, Quantity: qty, Cost: cost})
+}
+
+// AddFromInventory records that an item is taken from the inventory rather than bought, along with the opportunity
+// cost of using it rather than selling it.
+func (po *PurchaseOrder) AddFromInventory(id, qty, cost int) {
+	po.Inventory = addPurchaseItem(po.Inventory, PurchaseItem{ID: id, Quantity: qty, Cost: cost})
+	po.OpportunityCost += cost
+}
+
+// AddFarmed records that an item is farmed by the player rather than bought, along with the value of the time spent
+// farming it.
+func (po *PurchaseOrder) AddFarmed(id, qty, cost int) {
+	po.Farmed = addPurchaseItem(po.Farmed, PurchaseItem{ID: id, Quantity: qty, Cost: cost})
+	po.FarmCost += cost
+}
+
+// Merge adds the contents of another purchase order to this one.
+func (po *PurchaseOrder) Merge(other *PurchaseOrder) {
+	if other == nil {
+		return
+	}
+
+	for _, v := range other.Vendor {
+		po.Vendor = addPurchaseItem(po.Vendor, v)
+	}
+	for _, v := range other.AuctionHouse {
+		po.AuctionHouse = addPurchaseItem(po.AuctionHouse, v)
+	}
+	for _, v := range other.Crafted {
+		po.Crafted = addPurchaseItem(po.Crafted, v)
+	}
+	for _, v := range other.Inventory {
+		po.Inventory = addPurchaseItem(po.Inventory, v)
+	}
+	for _, v := range other.Farmed {
+		po.Farmed = addPurchaseItem(po.Farmed, v)
+	}
+	for _, v := range other.Converted {
+		po.Converted = addPurchaseItem(po.Converted, v)
+	}
+	po.Cost += other.Cost
+	po.OpportunityCost += other.OpportunityCost
+	po.FarmCost += other.FarmCost
+}
+
+// addPurchaseItem adds an item to a list, combining it with an existing entry for the same item if one exists.
+func addPurchaseItem(list []PurchaseItem, item PurchaseItem) []PurchaseItem {
+	for i := range list {
+		if list[i].ID == item.ID {
+			list[i].Quantity += item.Quantity
+			list[i].Cost += item.Cost
+			return list
+		}
+	}
+
+	return append(list, item)
+}
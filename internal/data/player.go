@@ -1,17 +1,27 @@
 package data
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 type Player struct {
-	Profession     Profession
-	AuctionHouseID int
-	SkillCurrent   int
-	SkillDesired   int
-	Recipes        map[int]bool
-	Inventory      map[int]int
+	Profession          Profession
+	SecondaryProfession Profession
+	Race                Race
+	Faction             Faction
+	AuctionHouseID      int
+	SkillCurrent        int
+	SkillDesired        int
+	SkillSecondary      int
+	Recipes             map[int]bool
+	Inventory           map[int]int
 }
 
 type InventoryItem struct {
-	ID       int
-	Quantity int
+	ID       int `json:"id"`
+	Quantity int `json:"quantity"`
 }
 
 func NewPlayer(profession Profession, skillCurrent, skillDesired, auctionHouseID int) *Player {
@@ -26,6 +36,38 @@ func NewPlayer(profession Profession, skillCurrent, skillDesired, auctionHouseID
 	}
 }
 
+// ParseRecipeExport parses a list of known recipes exported from the game, being the recipes spell ID's separated by
+// commas. The list may optionally begin with the profession ID followed by a colon, e.g. '197:3914,3915,12045', in
+// which case the profession must match the one provided.
+func ParseRecipeExport(export string, profession Profession) ([]int, error) {
+	var res []int
+
+	if header, list, ok := strings.Cut(export, ":"); ok {
+		id, err := strconv.Atoi(strings.TrimSpace(header))
+		if err != nil {
+			return nil, fmt.Errorf("invalid profession %q", header)
+		}
+		if Profession(id) != profession {
+			return nil, fmt.Errorf("exported for %v rather than %v", Profession(id), profession)
+		}
+		export = list
+	}
+
+	for _, v := range strings.Split(export, ",") {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+
+		id, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipe id %q", v)
+		}
+		res = append(res, id)
+	}
+
+	return res, nil
+}
+
 // AddRecipe adds a recipe ID to a players list of known recipe's
 func (p *Player) AddRecipe(id int) {
 	p.Recipes[id] = true
@@ -36,6 +78,45 @@ func (p *Player) HasRecipe(id int) bool {
 	return p.Recipes[id]
 }
 
+// SkillBonus returns the players racial bonus to the skill of a profession. The bonus counts towards the colour of
+// recipes, but not towards the skill cap of their trainer rank.
+func (p *Player) SkillBonus(profession Profession) int {
+	return p.Race.ProfessionBonus(profession)
+}
+
+// CanCraft returns whether the player is able to craft a recipe, either with the profession being levelled at the
+// provided skill or with their secondary profession, if they have the skill for it. Recipes restricted to the other
+// faction can't be crafted.
+func (p *Player) CanCraft(r *Recipe, skill int) bool {
+	if len(r.Profession) == 0 || !r.Faction.Allows(p.Faction) {
+		return false
+	}
+
+	switch r.Profession[0] {
+	case p.Profession:
+		return r.RequiredSkill() <= skill+p.SkillBonus(p.Profession)
+	case p.SecondaryProfession:
+		return r.RequiredSkill() <= p.SkillSecondary+p.SkillBonus(p.SecondaryProfession)
+	default:
+		return false
+	}
+}
+
+// CanConvert returns whether the player is able to perform a conversion, either with the profession being levelled at
+// the provided skill or with their secondary profession, if they have the skill for it.
+func (p *Player) CanConvert(c *Conversion, skill int) bool {
+	switch c.Method.Profession() {
+	case 0:
+		return false
+	case p.Profession:
+		return c.Skill <= skill+p.SkillBonus(p.Profession)
+	case p.SecondaryProfession:
+		return c.Skill <= p.SkillSecondary+p.SkillBonus(p.SecondaryProfession)
+	default:
+		return false
+	}
+}
+
 // CheckInventory returns the players quantity of a given item in their inventory
 func (p *Player) CheckInventory(id int) int {
 	if val, ok := p.Inventory[id]; ok {
@@ -44,16 +125,12 @@ func (p *Player) CheckInventory(id int) int {
 	return 0
 }
 
+// AddInventory adds a quantity of an item to the players inventory
 func (p *Player) AddInventory(id, qty int) {
-	val, ok := p.Inventory[id]
-	if !ok {
-		p.Inventory[id] = val
-		return
-	}
-
-	p.Inventory[id] = val + qty
+	p.Inventory[id] += qty
 }
 
+// ConsumeInventory removes a quantity of an item from the players inventory, returning whether they had enough of it
 func (p *Player) ConsumeInventory(id, qty int) bool {
 	val, ok := p.Inventory[id]
 	if !ok {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -16,16 +17,37 @@ type Recipe struct {
 	Name         string       `json:"name"`
 	Source       []Source     `json:"source"`
 	LearnedAt    int          `json:"learnedat"`
-	Profession   []Profession `json:"skill"`  // 'skill' refers to a profession number
-	Colors       []int        `json:"colors"` // order is: orange, yellow, green, grey
-	Creates      []int        `json:"creates"`
+	Profession   []Profession `json:"skill"`   // 'skill' refers to a profession number
+	Colors       []int        `json:"colors"`  // order is: orange, yellow, green, grey
+	Creates      []int        `json:"creates"` // [itemID, minQuantity, maxQuantity]
 	Reagents     [][]int      `json:"reagents"`
 	TrainingCost int          `json:"trainingcost"`
+	Faction      Faction      `json:"faction,omitempty"`
+	Reputation   [][]int      `json:"reputation,omitempty"` // [[reputationID, standing]] for each faction selling the recipe
+
+	CastTime      int    `json:"casttime,omitempty"`      // milliseconds taken to craft the recipe once
+	Cooldown      int    `json:"cooldown,omitempty"`      // seconds before the recipe can be crafted again
+	CooldownGroup string `json:"cooldowngroup,omitempty"` // name of the cooldown shared with other recipes, e.g. transmutes
+}
+
+// DEFAULT_CAST_TIME is how long a craft is assumed to take when neither the recipe nor its profession says otherwise.
+const DEFAULT_CAST_TIME = 3 * time.Second
+
+// professionCastTimes are assumed cast times for the professions whose crafts typically take longer than
+// DEFAULT_CAST_TIME. They are estimates for the profession as a whole rather than measured per recipe, and only apply
+// to recipes whose own cast time isn't known.
+var professionCastTimes = map[Profession]time.Duration{
+	PROFESSION_BLACKSMITHING:  5 * time.Second,
+	PROFESSION_ENCHANTING:     5 * time.Second,
+	PROFESSION_ENGINEERING:    5 * time.Second,
+	PROFESSION_LEATHERWORKING: 5 * time.Second,
+	PROFESSION_TAILORING:      5 * time.Second,
 }
 
 type RecipeStore struct {
 	dataslice []Recipe
 	datamap   map[int]Recipe
+	created   map[int][]int // itemID -> IDs of the recipes creating it
 	logger    *zap.SugaredLogger
 }
 
@@ -43,13 +65,18 @@ func NewRecipeStore(logger *zap.SugaredLogger) *RecipeStore {
 	}
 
 	datamap := make(map[int]Recipe, len(data))
+	created := make(map[int][]int)
 	for _, r := range data {
 		datamap[r.ID] = r
+		if len(r.Creates) > 0 {
+			created[r.Creates[0]] = append(created[r.Creates[0]], r.ID)
+		}
 	}
 
 	return &RecipeStore{
 		dataslice: data,
 		datamap:   datamap,
+		created:   created,
 		logger:    logger,
 	}
 }
@@ -62,6 +89,18 @@ func (r *RecipeStore) GetByID(id int) (*Recipe, error) {
 	return nil, fmt.Errorf("couldn't locate recipe with id %v", id)
 }
 
+// GetByCreated returns all recipes that create the provided item.
+func (r *RecipeStore) GetByCreated(id int) []*Recipe {
+	var res []*Recipe
+
+	for _, v := range r.created[id] {
+		recipe := r.datamap[v]
+		res = append(res, &recipe)
+	}
+
+	return res
+}
+
 // GetBatchByID returns a slice of recipes from their provided slice of ID's
 func (r *RecipeStore) GetBatchByID(ids []int) ([]*Recipe, error) {
 	var res []*Recipe
@@ -81,14 +120,18 @@ func (r *RecipeStore) GetBatchByID(ids []int) ([]*Recipe, error) {
 func (r *RecipeStore) GetFiltered(
 	currentSkill int,
 	profession Profession,
+	faction Faction,
 	fSource *FilterSource,
 	fSkillup *FilterSkillup,
+	fLevel *FilterLevel,
+	fReputation *FilterReputation,
 ) []Recipe {
 	var res []Recipe
 
 	// Iterate through all recipe's in the slice and filter
 	for _, recipe := range r.dataslice {
-		if recipe.Profession[0] == profession && fSource.Filter(&recipe) && fSkillup.Filter(&recipe, currentSkill) {
+		if recipe.Profession[0] == profession && recipe.Faction.Allows(faction) && fSource.Filter(&recipe) && fSkillup.Filter(&recipe, currentSkill) && fLevel.Filter(&recipe) &&
+			fReputation.Filter(&recipe) {
 			res = append(res, recipe)
 		}
 	}
@@ -96,6 +139,74 @@ func (r *RecipeStore) GetFiltered(
 	return res
 }
 
+// CreatedItem returns the ID of the item the recipe creates, or zero if it doesn't create one (e.g. enchants).
+func (r *Recipe) CreatedItem() int {
+	if len(r.Creates) != 3 {
+		return 0
+	}
+	return r.Creates[0]
+}
+
+// Yield returns the expected quantity of the item created by a single craft of the recipe, which for variable yields
+// is the midpoint of the minimum and maximum.
+func (r *Recipe) Yield() float64 {
+	if r.CreatedItem() == 0 {
+		return 0
+	}
+	return float64(r.Creates[1]+r.Creates[2]) / 2
+}
+
+// RequiredSkill returns the skill level the player must have reached in the recipe's profession to craft it.
+func (r *Recipe) RequiredSkill() int {
+	return validator.Max(r.LearnedAt, r.Colors[ColorOrange])
+}
+
+// RequiredReputation returns the standing the player must have with a faction to buy the recipe from one of its
+// vendors, for each of the vendors selling it to players of the provided faction.
+func (r *Recipe) RequiredReputation(faction Faction) map[Reputation]Standing {
+	res := make(map[Reputation]Standing)
+
+	for _, v := range r.Reputation {
+		if len(v) != 2 || !Reputation(v[0]).Faction().Allows(faction) {
+			continue
+		}
+		res[Reputation(v[0])] = Standing(v[1])
+	}
+
+	return res
+}
+
+// CastDuration returns how long the recipe takes to craft once. When it isn't known it is assumed to be the typical cast
+// time of the recipe's profession, see professionCastTimes.
+func (r *Recipe) CastDuration() time.Duration {
+	if r.CastTime <= 0 {
+		if len(r.Profession) > 0 {
+			if d, ok := professionCastTimes[r.Profession[0]]; ok {
+				return d
+			}
+		}
+		return DEFAULT_CAST_TIME
+	}
+	return time.Duration(r.CastTime) * time.Millisecond
+}
+
+// CooldownName returns the name of the cooldown a recipe is subject to, which is shared by every recipe in the same
+// group, or is the recipe's own otherwise.
+func (r *Recipe) CooldownName() string {
+	if r.CooldownGroup != "" {
+		return r.CooldownGroup
+	}
+	return r.Name
+}
+
+// SkillupChance returns the probability of a single craft of the recipe granting a skillup at the provided skill level.
+// Orange recipes always succeed, while the chance falls linearly from yellow until it reaches zero at grey.
+func (r *Recipe) SkillupChance(skill int) float64 {
+	chance := float64(r.Colors[ColorGrey]-skill) / float64(r.Colors[ColorGrey]-r.Colors[ColorYellow])
+
+	return validator.Min(validator.Max(chance, 0), 1)
+}
+
 // Validate determines whether a provided Recipe contains full and valid information.
 // TODO: Use or remove
 func (r *Recipe) Validate(v *validator.Validator) {
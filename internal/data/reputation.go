@@ -0,0 +1,90 @@
+package data
+
+// Represents the factions a player can earn reputation with, using the same numbers as the game. Many vendor recipes
+// can only be bought once the player has earned enough standing with the vendors faction.
+type Reputation int
+
+const (
+	REPUTATION_UNDEFINED                 Reputation = 0
+	REPUTATION_MAGHAR                    Reputation = 941
+	REPUTATION_CENARION_EXPEDITION       Reputation = 942
+	REPUTATION_HONOR_HOLD                Reputation = 946
+	REPUTATION_THRALLMAR                 Reputation = 947
+	REPUTATION_KURENAI                   Reputation = 978
+	REPUTATION_KALUAK                    Reputation = 1073
+	REPUTATION_KIRIN_TOR                 Reputation = 1090
+	REPUTATION_WYRMREST_ACCORD           Reputation = 1091
+	REPUTATION_KNIGHTS_OF_THE_EBON_BLADE Reputation = 1098
+	REPUTATION_ARGENT_CRUSADE            Reputation = 1106
+	REPUTATION_SONS_OF_HODIR             Reputation = 1119
+)
+
+func (r Reputation) String() string {
+	switch r {
+	case REPUTATION_MAGHAR:
+		return "mag'har"
+	case REPUTATION_CENARION_EXPEDITION:
+		return "cenarion expedition"
+	case REPUTATION_HONOR_HOLD:
+		return "honor hold"
+	case REPUTATION_THRALLMAR:
+		return "thrallmar"
+	case REPUTATION_KURENAI:
+		return "kurenai"
+	case REPUTATION_KALUAK:
+		return "the kalu'ak"
+	case REPUTATION_KIRIN_TOR:
+		return "kirin tor"
+	case REPUTATION_WYRMREST_ACCORD:
+		return "wyrmrest accord"
+	case REPUTATION_KNIGHTS_OF_THE_EBON_BLADE:
+		return "knights of the ebon blade"
+	case REPUTATION_ARGENT_CRUSADE:
+		return "argent crusade"
+	case REPUTATION_SONS_OF_HODIR:
+		return "sons of hodir"
+	default:
+		return UNDEFINED_TYPE
+	}
+}
+
+// Faction returns the only faction able to earn reputation with the faction, or FACTION_UNDEFINED if both can.
+func (r Reputation) Faction() Faction {
+	switch r {
+	case REPUTATION_HONOR_HOLD, REPUTATION_KURENAI:
+		return FACTION_ALLIANCE
+	case REPUTATION_THRALLMAR, REPUTATION_MAGHAR:
+		return FACTION_HORDE
+	default:
+		return FACTION_UNDEFINED
+	}
+}
+
+// Represents the standing a player has earned with a faction. Vendors sell recipes from friendly onwards.
+type Standing int
+
+const (
+	STANDING_UNDEFINED Standing = iota
+	STANDING_NEUTRAL
+	STANDING_FRIENDLY
+	STANDING_HONORED
+	STANDING_REVERED
+	STANDING_EXALTED
+)
+
+func (s Standing) String() string {
+	switch s {
+	case STANDING_NEUTRAL:
+		return "neutral"
+	case STANDING_FRIENDLY:
+		return "friendly"
+	case STANDING_HONORED:
+		return "honored"
+	case STANDING_REVERED:
+		return "revered"
+	case STANDING_EXALTED:
+		return "exalted"
+	default:
+		return UNDEFINED_TYPE
+	}
+}
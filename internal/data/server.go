@@ -12,7 +12,15 @@ import (
 type Server struct {
 	Name   string `json:"name"`
 	Region string `json:"region"`
-	AHIds  []int  `json:"auctionhouseids"`
+	AHIds  []int  `json:"auctionhouseids"` // alliance, then horde
+}
+
+// AuctionHouseID returns the ID of the servers auction house used by the provided faction.
+func (s *Server) AuctionHouseID(f Faction) int {
+	if f < FACTION_ALLIANCE || int(f) > len(s.AHIds) {
+		return 0
+	}
+	return s.AHIds[f-1]
 }
 
 type ServerStore struct {
@@ -3,6 +3,7 @@ package data
 import "go.uber.org/zap"
 
 type Stores struct {
+	Conversions *ConversionStore
 	Items       *ItemStore
 	NexusHub    *NexusHubStore
 	Recipes     *RecipeStore
@@ -12,6 +13,7 @@ type Stores struct {
 
 func NewStores(logger *zap.SugaredLogger) *Stores {
 	return &Stores{
+		Conversions: NewConversionStore(logger),
 		Items:       NewItemStore(logger),
 		NexusHub:    NewNexusHubStore(logger),
 		Recipes:     NewRecipeStore(logger),
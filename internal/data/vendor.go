@@ -9,10 +9,11 @@ import (
 )
 
 type VendorItem struct {
-	ID     int      `json:"id"`
-	Name   string   `json:"name"`
-	Source []Source `json:"source"`
-	Cost   int      `json:"cost"`
+	ID      int      `json:"id"`
+	Name    string   `json:"name"`
+	Source  []Source `json:"source"`
+	Cost    int      `json:"cost"`
+	Faction Faction  `json:"faction,omitempty"` // only sold by vendors of the faction
 }
 
 // Holds the data retrieved from static JSON files for Items
@@ -87,14 +87,14 @@ func (ts *TSMService) AuthTicker() {
 	}()
 }
 
-// Returns the current minBuyout for a provided item
+// Returns the price for a provided item from the provided source of pricing data, defaulting to its market value
 func (i *TSMItemRes) Price(pricingType string) int {
 	switch {
-	case strings.EqualFold(pricingType, "marketvalue"):
+	case strings.EqualFold(pricingType, PRICE_MARKET_VALUE):
 		return i.MarketValue
-	case strings.EqualFold(pricingType, "historical"):
+	case strings.EqualFold(pricingType, PRICE_HISTORICAL):
 		return i.Historical
-	case strings.EqualFold(pricingType, "minbuyout"):
+	case strings.EqualFold(pricingType, PRICE_MIN_BUYOUT):
 		return i.MinBuyout
 	default:
 		return i.MarketValue
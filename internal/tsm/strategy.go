@@ -0,0 +1,86 @@
+package tsm
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/chrishollman/WotLK-Profession-Leveller/internal/validator"
+)
+
+// Sources of pricing data provided by TSM
+const (
+	PRICE_MIN_BUYOUT   string = "minbuyout"
+	PRICE_MARKET_VALUE string = "marketvalue"
+	PRICE_HISTORICAL   string = "historical"
+)
+
+// Ways of combining multiple sources of pricing data
+const (
+	PRICE_BLEND string = "blend"
+	PRICE_MAX   string = "max"
+)
+
+// PricingStrategy determines how an item on the auction house is priced. The mode is either a single source of pricing
+// data, a weighted blend of several sources, or the maximum of several sources.
+type PricingStrategy struct {
+	Mode    string          `json:"mode"`
+	Sources []PricingSource `json:"sources,omitempty"`
+}
+
+// PricingSource is a single source of pricing data used by a blended or maximum pricing strategy. Weights are only
+// used when blending.
+type PricingSource struct {
+	Source string  `json:"source"`
+	Weight float64 `json:"weight,omitempty"`
+}
+
+// NewPricingStrategy returns a pricing strategy using a single source of pricing data.
+func NewPricingStrategy(source string) *PricingStrategy {
+	return &PricingStrategy{Mode: source}
+}
+
+// Price returns the price of an item according to the strategy.
+func (ps *PricingStrategy) Price(i *TSMItemRes) int {
+	switch ps.Mode {
+	case PRICE_BLEND:
+		var price float64
+		for _, s := range ps.Sources {
+			price += s.Weight * float64(i.Price(s.Source))
+		}
+		return int(math.Round(price))
+	case PRICE_MAX:
+		price := 0
+		for _, s := range ps.Sources {
+			price = validator.Max(price, i.Price(s.Source))
+		}
+		return price
+	default:
+		return i.Price(ps.Mode)
+	}
+}
+
+// Validate determines whether the pricing strategy is one that can be used.
+func (ps *PricingStrategy) Validate(v *validator.Validator) {
+	sources := []string{PRICE_MIN_BUYOUT, PRICE_MARKET_VALUE, PRICE_HISTORICAL}
+	modes := append([]string{PRICE_BLEND, PRICE_MAX}, sources...)
+
+	v.Check(validator.PermittedValue(ps.Mode, modes), "pricing.mode", fmt.Sprintf("must be one of %v", modes))
+
+	switch ps.Mode {
+	case PRICE_BLEND, PRICE_MAX:
+		v.Check(len(ps.Sources) >= 2, "pricing.sources", "must have two or more nominated")
+	default:
+		v.Check(len(ps.Sources) == 0, "pricing.sources", "must only be provided for blend and max")
+	}
+
+	var total float64
+	for _, s := range ps.Sources {
+		v.Check(validator.PermittedValue(s.Source, sources), "pricing.sources", fmt.Sprintf("must each be one of %v", sources))
+		v.Check(s.Weight >= 0, "pricing.sources", "must not have negative weights")
+		total += s.Weight
+	}
+
+	if ps.Mode == PRICE_BLEND {
+		v.Check(math.Abs(total-1) < 0.0001, "pricing.sources", "must have weights summing to 1")
+	}
+}